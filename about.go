@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+func orUnknown(val string) string {
+	if val == "" {
+		return tr("Unknown")
+	}
+	return val
+}
+
+func (a *App) aboutHandler() {
+	metadata := a.app.Metadata()
+	version := tr("Development build")
+	if metadata.Version != "" {
+		version = fmt.Sprintf(tr("%s (build %d)"), metadata.Version, metadata.Build)
+	}
+
+	aboutDialog := dialog.NewCustom(tr("About"), tr("Close"), widget.NewForm(
+		widget.NewFormItem(tr("Version"), widget.NewLabel(version)),
+		widget.NewFormItem(tr("Data schema"), widget.NewLabel(strconv.Itoa(a.source.SchemaVersion))),
+		widget.NewFormItem(tr("Game version"), widget.NewLabel(orUnknown(a.source.GameVersion))),
+		widget.NewFormItem(tr("Data updated"), widget.NewLabel(orUnknown(a.source.Updated))),
+		widget.NewFormItem(tr("Data"), widget.NewLabel(fmt.Sprintf(tr("%d ores, %d alloys, %d items"),
+			len(a.source.Ores), len(a.source.Alloys), len(a.source.Items)))),
+	), a.mainWindow)
+	aboutDialog.Show()
+}
@@ -1,489 +1,664 @@
-package main
-
-import (
-	"fmt"
-	"image/color"
-	"maps"
-	"math"
-	"slices"
-	"strconv"
-
-	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/app"
-	"fyne.io/fyne/v2/canvas"
-	"fyne.io/fyne/v2/container"
-	"fyne.io/fyne/v2/data/validation"
-	"fyne.io/fyne/v2/dialog"
-	"fyne.io/fyne/v2/layout"
-	"fyne.io/fyne/v2/theme"
-	"fyne.io/fyne/v2/widget"
-	"github.com/dustin/go-humanize"
-)
-
-type App struct {
-	app                fyne.App
-	mainWindow         fyne.Window
-	data               map[string]GameItem
-	itemList           []string
-	orders             []Ingredient
-	results            []Ingredient
-	craftingEfficiency bool
-	smeltingEfficiency bool
-	craftValBonus      float64
-	smeltValBonus      float64
-	underforgeBonus    float64
-	dormsBonus         float64
-	orderContainer     *fyne.Container
-	bonusContainer     *fyne.Container
-	resultSummary      *SummaryScreen
-	summaryAccordion   *widget.Accordion
-	resultTable        *widget.Table
-}
-
-func NewApp(data *jsonGameData) (app *App) {
-	gameData := getGameData(data)
-	app = &App{
-		data:     gameData,
-		itemList: getItemList(gameData),
-		orders:   make([]Ingredient, 0),
-		results:  make([]Ingredient, 0),
-	}
-	return
-}
-
-func (a *App) newOrderHandler() {
-	item := NewOrder(a.itemList)
-	a.orderContainer.Add(item)
-	item.SetOnRemoved(func() {
-		a.orderContainer.Remove(item)
-	})
-	item.SetOnItemChanged(func(name string) {
-		gameItem := a.data[name]
-		item.orderItem = gameItem
-	})
-}
-
-func (a *App) getResultsTable() *widget.Table {
-	resultTable := widget.NewTable(
-		func() (rows int, cols int) {
-			return len(a.results), 3
-		},
-		func() fyne.CanvasObject {
-			label := widget.NewLabel("template")
-			label.SizeName = theme.SizeNameCaptionText
-			return label
-		},
-		func(tci widget.TableCellID, co fyne.CanvasObject) {
-			var text string
-			switch tci.Col {
-			case 0:
-				text = a.results[tci.Row].Item.Name
-			case 1:
-				text = humanize.Comma(int64(a.results[tci.Row].Amount))
-			case 2:
-				text = fmt.Sprintf("$%s", humanize.Comma(int64(a.results[tci.Row].Value)))
-			default:
-				text = "Template"
-			}
-			co.(*widget.Label).SetText(text)
-		},
-	)
-	resultTable.SetColumnWidth(0, 120)
-	resultTable.SetColumnWidth(1, 80)
-	resultTable.SetColumnWidth(2, 120)
-	resultTable.ShowHeaderRow = true
-	resultTable.CreateHeader = func() fyne.CanvasObject {
-		label := widget.NewLabel("template")
-		return label
-	}
-	calcHeader := func(id widget.TableCellID) string {
-		switch id.Col {
-		case 0:
-			return "Item"
-		case 1:
-			return "Amount"
-		case 2:
-			return "Value"
-		default:
-			return "Template"
-		}
-	}
-	resultTable.UpdateHeader = func(id widget.TableCellID, template fyne.CanvasObject) {
-		template.(*widget.Label).SetText(calcHeader(id))
-	}
-	return resultTable
-}
-
-func (a *App) displayResults(ingredients []Ingredient) {
-	a.results = ingredients
-	a.resultTable.Refresh()
-	a.resultSummary.Display(a.getDisplayResults(a.orders))
-	a.resultSummary.Refresh()
-	a.summaryAccordion.Refresh()
-}
-
-func (a *App) getDisplayResults(order []Ingredient) []ResultItem {
-	result := make([]ResultItem, 0)
-	for _, o := range order {
-		order := ResultItem{
-			Amount:      o.Amount,
-			Name:        o.Item.Name,
-			Value:       a.getBonusedValue(o.Item.Type, o.Item.Value) * o.Amount,
-			Ingredients: make([]ResultItem, 0),
-		}
-		println(a.getBonusedValue(o.Item.Type, o.Item.Value))
-
-		for _, i := range o.Item.Ingredients {
-			order.Ingredients = append(order.Ingredients, ResultItem{
-				Amount: a.getBonusedMaterialAmount(o.Item.Type, i.Amount) * o.Amount,
-				Name:   i.Item.Name,
-				Value:  a.getBonusedValue(i.Item.Type, i.Value) * o.Amount,
-			})
-		}
-		result = append(result, order)
-	}
-	return result
-}
-
-func (a *App) sortResults(ingredients map[string]Ingredient) []Ingredient {
-	result := slices.Collect(maps.Values(ingredients))
-	slices.SortFunc(result, func(a, b Ingredient) int {
-		if a.Item.Type != b.Item.Type {
-			if a.Item.Type > b.Item.Type {
-				return -1
-			} else {
-				return 1
-			}
-		}
-		if a.Value != b.Value {
-			if a.Value < b.Value {
-				return 1
-			} else {
-				return -1
-			}
-		}
-		return 0
-	})
-	return result
-}
-
-func (a *App) calcResultsHandler() {
-	a.orders = make([]Ingredient, 0)
-	for _, o := range a.orderContainer.Objects {
-		a.orders = append(a.orders, Ingredient{
-			Item:   o.(*Order).orderItem,
-			Amount: o.(*Order).amount,
-		})
-	}
-	result := a.calculateIngredients(a.orders)
-	a.displayResults(a.sortResults(result))
-}
-
-func (a *App) getOrderAccordion(newOrderButton *widget.Button) *widget.Accordion {
-	bonusDialog := dialog.NewCustom("Bonuses", "Close", a.bonusContainer, a.mainWindow)
-	bonusDialog.Resize(bonusDialog.MinSize().AddWidthHeight(30, 0))
-	bonusButton := widget.NewButtonWithIcon("Bonuses", theme.SettingsIcon(), func() {
-		bonusDialog.Show()
-	})
-	return widget.NewAccordion(
-		widget.NewAccordionItem("Orders", container.NewVBox(
-			a.orderContainer,
-			container.NewHBox(
-				newOrderButton,
-				layout.NewSpacer(),
-				bonusButton,
-			),
-		)),
-	)
-}
-
-func (a *App) getBonuses() *fyne.Container {
-	getVal := func(input string) float64 {
-		val, err := strconv.ParseFloat(input, 32)
-		if err != nil || val == float64(0) {
-			val = 1.0
-		}
-		return val
-	}
-	getFormattedEntry := func() *widget.Entry {
-		entry := widget.NewEntry()
-		entry.Resize(entry.MinSize().AddWidthHeight(20, 0))
-		entry.Validator = validation.NewRegexp("^[0-9.]+$", "Numbers only please")
-		entry.OnSubmitted = func(input string) {
-			entry.SetText(fmt.Sprintf("%.2f", getVal(entry.Text)))
-		}
-		return entry
-	}
-	smeltEfficiency := widget.NewCheck("", func(input bool) {
-		a.smeltingEfficiency = input
-	})
-	craftEfficiency := widget.NewCheck("", func(input bool) {
-		a.craftingEfficiency = input
-	})
-	craftValEntry := getFormattedEntry()
-	smeltValEntry := getFormattedEntry()
-	underforgeEntry := getFormattedEntry()
-	dormsEntry := getFormattedEntry()
-
-	craftValEntry.OnChanged = func(input string) {
-		val := getVal(input)
-		a.craftValBonus = val
-	}
-	smeltValEntry.OnChanged = func(input string) {
-		val := getVal(input)
-		a.smeltValBonus = val
-	}
-	underforgeEntry.OnChanged = func(input string) {
-		val := getVal(input)
-		a.underforgeBonus = val
-	}
-	dormsEntry.OnChanged = func(input string) {
-		val := getVal(input)
-		a.dormsBonus = val
-	}
-
-	craftEfficiency.Checked = a.craftingEfficiency
-	smeltEfficiency.Checked = a.smeltingEfficiency
-	craftValEntry.SetText(fmt.Sprintf("%.2f", a.craftValBonus))
-	smeltValEntry.SetText(fmt.Sprintf("%.2f", a.smeltValBonus))
-	underforgeEntry.SetText(fmt.Sprintf("%.2f", a.underforgeBonus))
-	dormsEntry.SetText(fmt.Sprintf("%.2f", a.dormsBonus))
-
-	bonuses := widget.NewForm(
-		widget.NewFormItem("Craft Eff.", craftEfficiency),
-		widget.NewFormItem("Smelt Eff.", smeltEfficiency),
-		widget.NewFormItem("Smelt Value", smeltValEntry),
-		widget.NewFormItem("Craft Value", craftValEntry),
-		widget.NewFormItem("Underforge", underforgeEntry),
-		widget.NewFormItem("Dorms", dormsEntry),
-	)
-
-	return container.NewVBox(bonuses)
-}
-
-func (a *App) onStopped() {
-	a.app.Preferences().SetBool("smeltingEfficiency", a.smeltingEfficiency)
-	a.app.Preferences().SetBool("craftingEfficiency", a.craftingEfficiency)
-	a.app.Preferences().SetFloat("craftValBonus", a.craftValBonus)
-	a.app.Preferences().SetFloat("smeltValBonus", a.smeltValBonus)
-	a.app.Preferences().SetFloat("dormsBonus", a.dormsBonus)
-	a.app.Preferences().SetFloat("forgeBonus", a.underforgeBonus)
-}
-
-func (a *App) loadPreferences() {
-	a.smeltingEfficiency = a.app.Preferences().BoolWithFallback("smeltingEfficiency", false)
-	a.craftingEfficiency = a.app.Preferences().BoolWithFallback("craftingEfficiency", false)
-	a.craftValBonus = a.app.Preferences().FloatWithFallback("craftValBonus", 1.0)
-	a.smeltValBonus = a.app.Preferences().FloatWithFallback("smeltValBonus", 1.0)
-	a.dormsBonus = a.app.Preferences().FloatWithFallback("dormsBonus", 1.0)
-	a.underforgeBonus = a.app.Preferences().FloatWithFallback("forgeBonus", 1.0)
-}
-
-func (a *App) Run() {
-	a.app = app.New()
-	a.mainWindow = a.app.NewWindow("Idle Planet Calc")
-
-	a.loadPreferences()
-	a.orderContainer = container.NewVBox()
-	a.bonusContainer = a.getBonuses()
-	a.resultTable = a.getResultsTable()
-	a.resultSummary = NewSummaryScreen()
-	newOrderButton := widget.NewButtonWithIcon("Add ", theme.ContentAddIcon(), a.newOrderHandler)
-	calculateButton := widget.NewButtonWithIcon("Calculate", theme.ViewRefreshIcon(), a.calcResultsHandler)
-	orderAccordion := a.getOrderAccordion(newOrderButton)
-	a.summaryAccordion = widget.NewAccordion(widget.NewAccordionItem("Summary", a.resultSummary))
-	a.app.Lifecycle().SetOnStopped(a.onStopped)
-
-	a.mainWindow.SetContent(
-		container.NewBorder(
-			container.NewVBox(
-				orderAccordion,
-				calculateButton,
-				getSeparator(),
-				a.summaryAccordion,
-			),
-			nil,
-			nil,
-			nil,
-			a.resultTable,
-		))
-	a.mainWindow.Resize(fyne.NewSize(400, 600))
-	a.mainWindow.ShowAndRun()
-}
-
-func getSeparator() *canvas.LinearGradient {
-	return canvas.NewHorizontalGradient(
-		color.RGBA{100, 100, 100, 255},
-		color.RGBA{22, 22, 22, 255})
-}
-
-func getItemList(data map[string]GameItem) (items []string) {
-	items = make([]string, 0)
-
-	sortedItem := slices.Collect(maps.Values(data))
-	slices.SortFunc(sortedItem, func(a, b GameItem) int {
-		if a.Type > b.Type {
-			return -1
-		}
-		if a.Type < b.Type {
-			return 1
-		}
-		if a.Value == b.Value {
-			return 0
-		}
-		if a.Value > b.Value {
-			return -1
-		}
-		return 1
-	})
-
-	for _, item := range sortedItem {
-		if item.Type == Ore {
-			continue
-		}
-		items = append(items, item.Name)
-	}
-	return
-}
-
-func getGameData(data *jsonGameData) map[string]GameItem {
-	gameItems := make(map[string]GameItem, 0)
-
-	for _, ore := range data.Ores {
-		gameItems[ore.Name] = GameItem{
-			Name:  ore.Name,
-			Value: ore.Value,
-			Type:  Ore,
-		}
-	}
-
-	for _, alloy := range data.Alloys {
-		item := GameItem{
-			Name:        alloy.Name,
-			Type:        Alloy,
-			Value:       alloy.Value,
-			Ingredients: make([]Ingredient, 0),
-		}
-
-		for _, i := range alloy.Ingredients {
-			ingredient, found := gameItems[i.Name]
-			if !found {
-				fmt.Printf("could not find: %s for %s\n", i.Name, alloy.Name)
-				continue
-			}
-			item.Ingredients = append(item.Ingredients, Ingredient{
-				Item:   ingredient,
-				Amount: i.Amount,
-			})
-		}
-
-		gameItems[alloy.Name] = item
-	}
-
-	for _, v := range data.Items {
-		item := GameItem{
-			Name:        v.Name,
-			Type:        Item,
-			Value:       v.Value,
-			Ingredients: make([]Ingredient, 0),
-		}
-
-		for _, i := range v.Ingredients {
-			ingredient, found := gameItems[i.Name]
-			if !found {
-				fmt.Printf("could not find: %s for %s", i.Name, v.Name)
-				continue
-			}
-			item.Ingredients = append(item.Ingredients, Ingredient{
-				Item:   ingredient,
-				Amount: i.Amount,
-			})
-		}
-
-		gameItems[v.Name] = item
-	}
-
-	return gameItems
-}
-
-func (a *App) calculateIngredients(order []Ingredient) (bill map[string]Ingredient) {
-	bill = make(map[string]Ingredient)
-	for _, o := range order {
-		ingredients := a.getIngredients(o.Item)
-		for _, i := range ingredients {
-			value := i.Item.Value * i.Amount * o.Amount
-			amount := i.Amount * o.Amount
-			if item, found := bill[i.Item.Name]; !found {
-				bill[i.Item.Name] = Ingredient{i.Item, value, amount}
-			} else {
-				item.Value += value
-				item.Amount += amount
-				bill[i.Item.Name] = item
-			}
-		}
-	}
-	return
-}
-
-func (a *App) getBonusedMaterialAmount(itemType ItemType, value int) int {
-	roomBonus := float64(1)
-	projectBonus := float64(1)
-	amount := float64(value)
-
-	if itemType == Item {
-		roomBonus = a.dormsBonus
-		if a.craftingEfficiency {
-			projectBonus = 1.2
-		}
-	} else {
-		roomBonus = a.underforgeBonus
-		if a.smeltingEfficiency {
-			projectBonus = 1.2
-		}
-	}
-
-	basePrice := amount - (amount * (roomBonus - 1))
-	smeltBonus := basePrice * (projectBonus - 1)
-	if smeltBonus < 1 {
-		smeltBonus = math.Round(smeltBonus)
-	}
-	return int(math.Round(basePrice - smeltBonus))
-}
-
-func (a *App) getBonusedValue(itemType ItemType, value int) int {
-	var projectBonus float64
-	amount := float64(value)
-	if itemType == Item {
-		projectBonus = a.craftValBonus
-	} else {
-		projectBonus = a.smeltValBonus
-	}
-	return int(math.Round(amount * projectBonus))
-}
-
-func (a *App) getIngredients(item GameItem) (ingredients map[string]Ingredient) {
-	ingredients = make(map[string]Ingredient, 0)
-	for _, i := range item.Ingredients {
-		amount := a.getBonusedMaterialAmount(item.Type, i.Amount)
-		value := a.getBonusedValue(i.Item.Type, i.Item.Value)
-
-		if newItem, found := ingredients[i.Item.Name]; !found {
-			ingredients[i.Item.Name] = Ingredient{i.Item, value, amount}
-		} else {
-			newItem.Amount += amount
-		}
-
-		if len(i.Item.Ingredients) > 0 {
-			subIngredients := a.getIngredients(i.Item)
-			for k, v := range subIngredients {
-				if newItem, found := ingredients[k]; !found {
-					ingredients[k] = Ingredient{v.Item, v.Value, v.Amount * amount}
-				} else {
-					newItem.Amount += v.Amount * amount
-					ingredients[k] = newItem
-				}
-			}
-		}
-	}
-	return
-}
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const recalcDelay = 300 * time.Millisecond
+
+// wideLayoutWidth is the window width from which orders and results are
+// shown side by side
+const wideLayoutWidth = 800
+
+type App struct {
+	app              fyne.App
+	mainWindow       fyne.Window
+	source           *jsonGameData
+	overrides        *jsonGameData
+	data             map[string]GameItem
+	itemList         []string
+	orders           []Ingredient
+	results          []Ingredient
+	shownResults     []Ingredient
+	plans            map[string]string
+	bonusProfiles    map[string]Bonuses
+	liveMode         bool
+	recalcTimer      *time.Timer
+	staleIndicator   *fyne.Container
+	orderContainer   *fyne.Container
+	bonusContainer   *fyne.Container
+	bonusButton      *widget.Button
+	calculateRow     *fyne.Container
+	toolbar          *widget.Toolbar
+	orderAccordion   *widget.Accordion
+	ordersSplit      *container.Split
+	summarySplit     *container.Split
+	bonusForm        *BonusForm
+	history          *History
+	icons            *IconSet
+	undoAction       *widget.ToolbarAction
+	redoAction       *widget.ToolbarAction
+	resultSummary    *SummaryScreen
+	summaryAccordion *widget.Accordion
+	resultTable      *widget.Table
+	resultFilter     *shortcutEntry
+	Bonuses
+}
+
+func NewApp(data *jsonGameData) (app *App) {
+	gameData := getGameData(data)
+	app = &App{
+		source:   data,
+		data:     gameData,
+		itemList: getItemList(gameData),
+		orders:   make([]Ingredient, 0),
+		results:  make([]Ingredient, 0),
+		history:  NewHistory(defaultHistoryDepth),
+		icons:    NewIconSet("", gameData),
+	}
+	return
+}
+
+func (a *App) newOrderHandler() {
+	before := a.getOrderRows()
+	a.addOrder(Ingredient{Amount: 1})
+	a.recordOrderRows(before)
+}
+
+type orderSnapshot struct {
+	rows   []fyne.CanvasObject
+	groups []string
+}
+
+func (a *App) getOrderRows() orderSnapshot {
+	snapshot := orderSnapshot{
+		rows:   slices.Clone(a.orderContainer.Objects),
+		groups: make([]string, 0),
+	}
+	for _, o := range snapshot.rows {
+		snapshot.groups = append(snapshot.groups, o.(*Order).group)
+	}
+	return snapshot
+}
+
+func (a *App) setOrderRows(snapshot orderSnapshot) {
+	a.orderContainer.Objects = slices.Clone(snapshot.rows)
+	for index, o := range snapshot.rows {
+		o.(*Order).group = snapshot.groups[index]
+	}
+	a.refreshOrderGroups()
+	a.inputsChanged()
+}
+
+// recordOrderRows adds the change from before to the current order rows to
+// the history, the rows keep their own item and amount.
+func (a *App) recordOrderRows(before orderSnapshot) {
+	after := a.getOrderRows()
+	a.history.Record("", func() {
+		a.setOrderRows(before)
+	}, func() {
+		a.setOrderRows(after)
+	})
+}
+
+func (a *App) addOrder(order Ingredient) *Order {
+	item := NewOrder(a.itemList, a.icons)
+	item.orderItem = order.Item
+	item.amount = order.Amount
+	item.group = order.Group
+	a.orderContainer.Add(item)
+	item.SetOnRemoved(func() {
+		before := a.getOrderRows()
+		a.orderContainer.Remove(item)
+		a.refreshOrderGroups()
+		a.recordOrderRows(before)
+		a.inputsChanged()
+	})
+	item.SetOnMoved(func(rows int) {
+		a.moveOrder(item, rows)
+	})
+	item.SetOnDuplicated(func() {
+		a.duplicateOrder(item)
+	})
+	item.SetOnGroupSelected(func() {
+		a.groupHandler(item)
+	})
+	item.SetOnItemChanged(func(name string) {
+		previous := item.orderItem
+		gameItem := a.data[name]
+		item.orderItem = gameItem
+		a.history.Record("", func() {
+			item.SetItem(previous)
+			a.inputsChanged()
+		}, func() {
+			item.SetItem(gameItem)
+			a.inputsChanged()
+		})
+		a.inputsChanged()
+	})
+	previousAmount := order.Amount
+	item.SetOnAmountChanged(func(amount int) {
+		previous := previousAmount
+		previousAmount = amount
+		a.history.Record(fmt.Sprintf("amount:%p", item), func() {
+			previousAmount = previous
+			item.SetAmount(previous)
+			a.inputsChanged()
+		}, func() {
+			previousAmount = amount
+			item.SetAmount(amount)
+			a.inputsChanged()
+		})
+		a.inputsChanged()
+	})
+	a.refreshOrderGroups()
+	a.inputsChanged()
+	return item
+}
+
+func (a *App) getOrders() []Ingredient {
+	orders := make([]Ingredient, 0)
+	for _, o := range a.orderContainer.Objects {
+		orders = append(orders, Ingredient{
+			Item:   o.(*Order).orderItem,
+			Amount: o.(*Order).amount,
+			Group:  o.(*Order).group,
+		})
+	}
+	return orders
+}
+
+func (a *App) getResultsTable() *widget.Table {
+	resultTable := widget.NewTable(
+		func() (rows int, cols int) {
+			return len(a.shownResults), 3
+		},
+		func() fyne.CanvasObject {
+			return NewIconLabel("template", nil)
+		},
+		func(tci widget.TableCellID, co fyne.CanvasObject) {
+			var text string
+			var icon fyne.Resource
+			switch tci.Col {
+			case 0:
+				text = itemName(a.shownResults[tci.Row].Item.Name)
+				icon = a.icons.Icon(a.shownResults[tci.Row].Item.Name)
+			case 1:
+				text = formatNumber(a.shownResults[tci.Row].Amount)
+			case 2:
+				text = formatMoney(a.shownResults[tci.Row].Value)
+			default:
+				text = "Template"
+			}
+			co.(*IconLabel).SetText(text)
+			co.(*IconLabel).SetIcon(icon)
+			if tci.Col == 0 {
+				co.(*IconLabel).SetColorName(a.icons.ColorName(a.shownResults[tci.Row].Item.Name))
+			} else {
+				co.(*IconLabel).SetColorName("")
+			}
+		},
+	)
+	resultTable.SetColumnWidth(0, 150)
+	resultTable.SetColumnWidth(1, 80)
+	resultTable.SetColumnWidth(2, 120)
+	resultTable.ShowHeaderRow = true
+	resultTable.CreateHeader = func() fyne.CanvasObject {
+		label := widget.NewLabel("template")
+		return label
+	}
+	calcHeader := func(id widget.TableCellID) string {
+		switch id.Col {
+		case 0:
+			return tr("Item")
+		case 1:
+			return tr("Amount")
+		case 2:
+			return tr("Value")
+		default:
+			return "Template"
+		}
+	}
+	resultTable.UpdateHeader = func(id widget.TableCellID, template fyne.CanvasObject) {
+		template.(*widget.Label).SetText(calcHeader(id))
+	}
+	return resultTable
+}
+
+func (a *App) displayResults(ingredients []Ingredient) {
+	a.results = ingredients
+	a.filterResults()
+	a.resultSummary.Display(a.getDisplayResults(a.orders))
+	a.resultSummary.Refresh()
+	a.summaryAccordion.Refresh()
+}
+
+// filterResults shows the results with names containing the filter text
+func (a *App) filterResults() {
+	filter := normaliseName(a.resultFilter.Text)
+	a.shownResults = slices.DeleteFunc(slices.Clone(a.results), func(result Ingredient) bool {
+		return !strings.Contains(normaliseName(itemName(result.Item.Name)), filter)
+	})
+	a.resultTable.Refresh()
+}
+
+// inputsChanged is called whenever an order or bonus changes, recalculating
+// after a short delay in live mode or flagging the results as stale.
+func (a *App) inputsChanged() {
+	if !a.liveMode {
+		a.staleIndicator.Show()
+		return
+	}
+	if a.recalcTimer != nil {
+		a.recalcTimer.Stop()
+	}
+	a.recalcTimer = time.AfterFunc(recalcDelay, func() {
+		fyne.Do(a.calcResultsHandler)
+	})
+}
+
+func (a *App) calcResultsHandler() {
+	a.staleIndicator.Hide()
+	a.orders = a.getOrders()
+	results, _ := a.calculate(a.orders)
+	a.displayResults(results)
+}
+
+func (a *App) getOrderAccordion(newOrderButton *widget.Button) *widget.Accordion {
+	a.bonusButton = widget.NewButtonWithIcon(tr("Bonuses"), theme.SettingsIcon(), a.bonusesHandler)
+	exportButton := widget.NewButtonWithIcon(tr("Export"), theme.DocumentSaveIcon(), a.exportHandler)
+	plansButton := widget.NewButtonWithIcon(tr("Plans"), theme.FolderIcon(), a.plansHandler)
+	return widget.NewAccordion(
+		widget.NewAccordionItem(tr("Orders"), container.NewVBox(
+			a.orderContainer,
+			container.NewHBox(
+				newOrderButton,
+				plansButton,
+				layout.NewSpacer(),
+				exportButton,
+				a.bonusButton,
+			),
+		)),
+	)
+}
+
+// bonusesHandler opens the bonuses, or when they're already showing beside
+// the orders moves the focus to them.
+func (a *App) bonusesHandler() {
+	if !a.bonusButton.Visible() {
+		a.mainWindow.Canvas().Focus(a.bonusForm.smeltValEntry)
+		return
+	}
+	bonusDialog := dialog.NewCustom(tr("Bonuses"), tr("Close"), a.bonusContainer, a.mainWindow)
+	bonusDialog.Resize(bonusDialog.MinSize().AddWidthHeight(30, 0))
+	bonusDialog.Show()
+}
+
+func (a *App) bonusesChanged(previous Bonuses) {
+	current := a.Bonuses
+	a.history.Record("bonuses", func() {
+		a.setBonuses(previous)
+	}, func() {
+		a.setBonuses(current)
+	})
+	a.inputsChanged()
+}
+
+func (a *App) setBonuses(bonuses Bonuses) {
+	a.Bonuses = bonuses
+	a.bonusForm.Refresh()
+	a.inputsChanged()
+}
+
+func (a *App) undo() {
+	a.history.Undo()
+}
+
+func (a *App) redo() {
+	a.history.Redo()
+}
+
+func (a *App) historyChanged() {
+	if a.undoAction == nil {
+		return
+	}
+	if a.history.CanUndo() {
+		a.undoAction.Enable()
+	} else {
+		a.undoAction.Disable()
+	}
+	if a.history.CanRedo() {
+		a.redoAction.Enable()
+	} else {
+		a.redoAction.Disable()
+	}
+}
+
+func (a *App) getToolbar() *widget.Toolbar {
+	a.undoAction = widget.NewToolbarAction(theme.ContentUndoIcon(), a.undo)
+	a.redoAction = widget.NewToolbarAction(theme.ContentRedoIcon(), a.redo)
+	a.history.SetOnChanged(a.historyChanged)
+	a.historyChanged()
+	menuAction := widget.NewToolbarAction(theme.MenuIcon(), nil)
+	menuAction.OnActivated = func() {
+		a.showToolsMenu(menuAction.ToolbarObject())
+	}
+	return widget.NewToolbar(
+		a.undoAction,
+		a.redoAction,
+		widget.NewToolbarSpacer(),
+		menuAction,
+	)
+}
+
+// showToolsMenu pops the tools menu up below button, kept inside the window
+func (a *App) showToolsMenu(button fyne.CanvasObject) {
+	menu := widget.NewPopUpMenu(fyne.NewMenu("",
+		fyne.NewMenuItem(tr("Cash goal..."), a.goalHandler),
+		fyne.NewMenuItem(tr("Simulate..."), a.simHandler),
+		fyne.NewMenuItem(tr("Compare bonuses..."), a.compareHandler),
+		fyne.NewMenuItem(tr("Inventory diff..."), a.diffHandler),
+		fyne.NewMenuItem(tr("Edit game data..."), a.editorHandler),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(tr("Settings..."), a.settingsHandler),
+		fyne.NewMenuItem(tr("About"), a.aboutHandler),
+	), a.mainWindow.Canvas())
+	position := a.app.Driver().AbsolutePositionForObject(button).AddXY(0, button.Size().Height)
+	position.X = min(position.X, a.mainWindow.Canvas().Size().Width-menu.MinSize().Width)
+	menu.ShowAtPosition(position)
+}
+
+func (a *App) focusFilter() {
+	a.mainWindow.Canvas().Focus(a.resultFilter)
+}
+
+// focusNewOrder adds an order and focuses its item so it can be picked
+// with the keyboard.
+func (a *App) focusNewOrder() {
+	a.orderAccordion.Open(0)
+	a.newOrderHandler()
+	order := a.orderContainer.Objects[len(a.orderContainer.Objects)-1].(*Order)
+	if order.renderer != nil {
+		a.mainWindow.Canvas().Focus(order.renderer.itemSelector)
+	}
+}
+
+func (a *App) removeFocusedOrder() {
+	focused := a.mainWindow.Canvas().Focused()
+	for _, o := range a.orderContainer.Objects {
+		if order := o.(*Order); order.hasFocus(focused) {
+			a.mainWindow.Canvas().Unfocus()
+			order.onRemoved()
+			return
+		}
+	}
+}
+
+func (a *App) addShortcuts() {
+	if _, ok := a.app.Driver().(desktop.Driver); !ok {
+		return
+	}
+	a.mainWindow.Canvas().AddShortcut(&fyne.ShortcutUndo{}, func(fyne.Shortcut) {
+		a.undo()
+	})
+	a.mainWindow.Canvas().AddShortcut(&fyne.ShortcutRedo{}, func(fyne.Shortcut) {
+		a.redo()
+	})
+
+	shortcuts := []struct {
+		key      fyne.KeyName
+		modifier fyne.KeyModifier
+		action   func()
+	}{
+		{fyne.KeyN, fyne.KeyModifierShortcutDefault, a.focusNewOrder},
+		{fyne.KeyReturn, fyne.KeyModifierShortcutDefault, a.calcResultsHandler},
+		{fyne.KeyB, fyne.KeyModifierShortcutDefault, a.bonusesHandler},
+		{fyne.KeyE, fyne.KeyModifierShortcutDefault, a.exportHandler},
+		{fyne.KeyF, fyne.KeyModifierShortcutDefault, a.focusFilter},
+		{fyne.KeyDelete, fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift, a.removeFocusedOrder},
+		{fyne.KeyK, fyne.KeyModifierShortcutDefault, a.paletteHandler},
+	}
+	for _, shortcut := range shortcuts {
+		a.mainWindow.Canvas().AddShortcut(&desktop.CustomShortcut{
+			KeyName:  shortcut.key,
+			Modifier: shortcut.modifier,
+		}, func(fyne.Shortcut) {
+			shortcut.action()
+		})
+	}
+}
+
+func (a *App) onStopped() {
+	saveBonuses(a.app.Preferences(), a.Bonuses)
+	a.saveSplitOffsets()
+	size := a.mainWindow.Canvas().Size()
+	a.app.Preferences().SetFloat("windowWidth", float64(size.Width))
+	a.app.Preferences().SetFloat("windowHeight", float64(size.Height))
+}
+
+func (a *App) saveSplitOffsets() {
+	if a.ordersSplit == nil {
+		return
+	}
+	a.app.Preferences().SetFloat("ordersSplit", a.ordersSplit.Offset)
+	a.app.Preferences().SetFloat("summarySplit", a.summarySplit.Offset)
+}
+
+// getLayout stacks everything in one column for phones and narrow windows,
+// wide ones get the orders and bonuses beside the summary and results.
+func (a *App) getLayout(wide bool) fyne.CanvasObject {
+	a.saveSplitOffsets()
+	if !wide {
+		a.ordersSplit, a.summarySplit = nil, nil
+		a.bonusButton.Show()
+		return container.NewBorder(
+			container.NewVBox(
+				a.toolbar,
+				a.orderAccordion,
+				a.calculateRow,
+				a.staleIndicator,
+				NewSeparator(),
+				a.summaryAccordion,
+			),
+			nil,
+			nil,
+			nil,
+			container.NewBorder(a.resultFilter, nil, nil, nil, a.resultTable),
+		)
+	}
+
+	a.bonusButton.Hide()
+	a.summarySplit = container.NewVSplit(
+		container.NewVScroll(a.summaryAccordion),
+		container.NewBorder(a.resultFilter, nil, nil, nil, a.resultTable),
+	)
+	a.summarySplit.Offset = a.app.Preferences().FloatWithFallback("summarySplit", 0.4)
+	a.ordersSplit = container.NewHSplit(
+		container.NewBorder(a.toolbar, nil, nil, nil, container.NewVScroll(container.NewVBox(
+			a.orderAccordion,
+			a.calculateRow,
+			a.staleIndicator,
+			NewSeparator(),
+			widget.NewLabelWithStyle(tr("Bonuses"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			a.bonusContainer,
+		))),
+		a.summarySplit,
+	)
+	a.ordersSplit.Offset = a.app.Preferences().FloatWithFallback("ordersSplit", 0.5)
+	return a.ordersSplit
+}
+
+func (a *App) loadPreferences() {
+	setLanguage(a.app.Preferences().String("language"))
+	numberFormat = NumberFormat(a.app.Preferences().IntWithFallback("numberFormat", int(FullNumbers)))
+	a.Bonuses = loadBonuses(a.app.Preferences())
+	a.liveMode = a.app.Preferences().BoolWithFallback("liveMode", false)
+	a.history.SetDepth(a.app.Preferences().IntWithFallback("historyDepth", defaultHistoryDepth))
+	a.loadPlans()
+	a.loadBonusProfiles()
+}
+
+// setup builds the main window on fyneApp without showing it, so tests can
+// drive it with a test app.
+func (a *App) setup(fyneApp fyne.App) {
+	a.app = fyneApp
+	a.mainWindow = a.app.NewWindow("Idle Planet Calc")
+
+	a.loadPreferences()
+	a.applyTheme()
+	a.loadOverrides()
+	a.icons.SetOverrideDir(a.getIconDir())
+	a.setGameData()
+	a.staleIndicator = container.NewHBox(
+		widget.NewIcon(theme.WarningIcon()),
+		widget.NewLabel(tr("Results are out of date")),
+	)
+	a.orderContainer = container.NewVBox()
+	a.bonusForm = NewBonusForm(&a.Bonuses, a.bonusesChanged)
+	a.bonusContainer = a.bonusForm.Container()
+	a.resultTable = a.getResultsTable()
+	a.resultFilter = newShortcutEntry()
+	a.resultFilter.SetPlaceHolder(tr("Filter results"))
+	a.resultFilter.OnChanged = func(string) { a.filterResults() }
+	a.resultSummary = NewSummaryScreen(a.icons)
+	a.staleIndicator.Hide()
+	newOrderButton := widget.NewButtonWithIcon(tr("Add")+" ", theme.ContentAddIcon(), a.newOrderHandler)
+	calculateButton := widget.NewButtonWithIcon(tr("Calculate"), theme.ViewRefreshIcon(), a.calcResultsHandler)
+	liveCheck := widget.NewCheck(tr("Live"), func(input bool) {
+		a.liveMode = input
+		a.app.Preferences().SetBool("liveMode", input)
+		a.inputsChanged()
+	})
+	liveCheck.Checked = a.liveMode
+	a.calculateRow = container.NewBorder(nil, nil, nil, liveCheck, calculateButton)
+	a.orderAccordion = a.getOrderAccordion(newOrderButton)
+	a.summaryAccordion = widget.NewAccordion(widget.NewAccordionItem(tr("Summary"), a.resultSummary))
+	a.app.Lifecycle().SetOnStopped(a.onStopped)
+	a.addShortcuts()
+
+	a.toolbar = a.getToolbar()
+
+	a.mainWindow.SetContent(NewAdaptiveContainer(wideLayoutWidth, a.getLayout))
+	a.mainWindow.Resize(fyne.NewSize(
+		float32(a.app.Preferences().FloatWithFallback("windowWidth", 400)),
+		float32(a.app.Preferences().FloatWithFallback("windowHeight", 600))))
+}
+
+func (a *App) Run() {
+	a.setup(app.NewWithID(appID))
+	a.mainWindow.ShowAndRun()
+}
+
+func getItemList(data map[string]GameItem) (items []string) {
+	items = make([]string, 0)
+
+	sortedItem := slices.Collect(maps.Values(data))
+	slices.SortFunc(sortedItem, func(a, b GameItem) int {
+		if a.Type > b.Type {
+			return -1
+		}
+		if a.Type < b.Type {
+			return 1
+		}
+		if a.Value == b.Value {
+			return 0
+		}
+		if a.Value > b.Value {
+			return -1
+		}
+		return 1
+	})
+
+	for _, item := range sortedItem {
+		if item.Type == Ore {
+			continue
+		}
+		items = append(items, item.Name)
+	}
+	return
+}
+
+func getGameData(data *jsonGameData) map[string]GameItem {
+	gameItems, errs := buildGameData(data)
+	for _, err := range errs {
+		fmt.Println(err)
+	}
+	return gameItems
+}
+
+// buildGameData resolves the ingredients of each alloy and item against the
+// ores, alloys and items listed before it, returning anything that doesn't
+// add up alongside the items that do.
+func buildGameData(data *jsonGameData) (map[string]GameItem, []error) {
+	gameItems := make(map[string]GameItem, 0)
+	errs := make([]error, 0)
+	boms := newBOMCache()
+
+	add := func(itemType ItemType, v jsonGameItem) {
+		if v.Name == "" {
+			errs = append(errs, fmt.Errorf("an %s has no name", strings.ToLower(itemType.String())))
+			return
+		}
+		if _, found := gameItems[v.Name]; found {
+			errs = append(errs, fmt.Errorf("%s is listed more than once", v.Name))
+		}
+		if v.Value < 0 || v.Time < 0 {
+			errs = append(errs, fmt.Errorf("%s can't have a negative value or time", v.Name))
+		}
+		item := GameItem{
+			Name:  v.Name,
+			Type:  itemType,
+			Value: v.Value,
+			Time:  v.Time,
+			boms:  boms,
+		}
+		if itemType != Ore {
+			item.Ingredients = make([]Ingredient, 0)
+		}
+
+		for _, i := range v.Ingredients {
+			if itemType == Ore {
+				errs = append(errs, fmt.Errorf("ore %s can't have ingredients", v.Name))
+				break
+			}
+			ingredient, found := gameItems[i.Name]
+			if !found {
+				errs = append(errs, fmt.Errorf("could not find: %s for %s", i.Name, v.Name))
+				continue
+			}
+			if i.Amount <= 0 {
+				errs = append(errs, fmt.Errorf("%s needs more than 0 %s", v.Name, i.Name))
+			}
+			item.Ingredients = append(item.Ingredients, Ingredient{
+				Item:   ingredient,
+				Amount: i.Amount,
+			})
+		}
+
+		gameItems[v.Name] = item
+	}
+
+	for _, ore := range data.Ores {
+		add(Ore, ore)
+	}
+	for _, alloy := range data.Alloys {
+		add(Alloy, alloy)
+	}
+	for _, v := range data.Items {
+		add(Item, v)
+	}
+	return gameItems, errs
+}
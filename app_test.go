@@ -0,0 +1,308 @@
+package main
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+// testStorage moves a test app's files, such as the game data overrides,
+// out of the shared temp directory.
+type testStorage struct {
+	fyne.Storage
+	root fyne.URI
+}
+
+func (s testStorage) RootURI() fyne.URI {
+	return s.root
+}
+
+type testApp struct {
+	fyne.App
+	storage fyne.Storage
+}
+
+func (a testApp) Storage() fyne.Storage {
+	return a.storage
+}
+
+// newTestApp sets up the main window on an in-memory app, in English so the
+// labels don't depend on the system language. Each test app gets a storage
+// directory of its own.
+func newTestApp(t *testing.T, fyneApp fyne.App) *App {
+	t.Helper()
+	if fyneApp == nil {
+		fyneApp = test.NewTempApp(t)
+		fyneApp.Preferences().SetString("language", "en")
+	}
+	a := NewApp(loadData())
+	a.setup(testApp{fyneApp, testStorage{fyneApp.Storage(), storage.NewFileURI(t.TempDir())}})
+	a.orderAccordion.Open(0)
+	a.summaryAccordion.Open(0)
+	a.mainWindow.Resize(fyne.NewSize(400, 800))
+	return a
+}
+
+func findButton(t *testing.T, a *App, text string) *widget.Button {
+	t.Helper()
+	for _, o := range test.LaidOutObjects(a.mainWindow.Content()) {
+		if button, ok := o.(*widget.Button); ok && button.Text == text {
+			return button
+		}
+	}
+	t.Fatalf("no %q button", text)
+	return nil
+}
+
+// addTestOrder adds an order with the Add button, picks the item by its
+// English name and types over the amount.
+func addTestOrder(t *testing.T, a *App, item string, amount string) *Order {
+	t.Helper()
+	test.Tap(findButton(t, a, tr("Add")+" "))
+	order := a.orderContainer.Objects[len(a.orderContainer.Objects)-1].(*Order)
+	test.WidgetRenderer(order)
+	order.renderer.itemSelector.SetSelected(itemName(item))
+	typeOver(order.renderer.amount, amount)
+	return order
+}
+
+func typeOver(entry *shortcutEntry, text string) {
+	entry.TypedShortcut(&fyne.ShortcutSelectAll{})
+	test.Type(entry, text)
+}
+
+// tableCells reads the results table through its cell callbacks
+func tableCells(table *widget.Table) [][]string {
+	rows, cols := table.Length()
+	cells := make([][]string, 0)
+	for row := range rows {
+		line := make([]string, 0)
+		for col := range cols {
+			cell := table.CreateCell()
+			table.UpdateCell(widget.TableCellID{Row: row, Col: col}, cell)
+			line = append(line, cell.(*IconLabel).label.Text)
+		}
+		cells = append(cells, line)
+	}
+	return cells
+}
+
+func labelTexts(o fyne.CanvasObject) []string {
+	texts := make([]string, 0)
+	for _, object := range test.LaidOutObjects(o) {
+		switch label := object.(type) {
+		case *widget.Label:
+			texts = append(texts, label.Text)
+		}
+	}
+	return texts
+}
+
+func TestCalculateOrders(t *testing.T) {
+	a := newTestApp(t, nil)
+	addTestOrder(t, a, "Copper Bar", "3")
+	addTestOrder(t, a, "Iron Bar", "2")
+	if !a.staleIndicator.Visible() {
+		t.Error("results should be stale after adding orders")
+	}
+	test.Tap(findButton(t, a, "Calculate"))
+	if a.staleIndicator.Visible() {
+		t.Error("results should be current after calculating")
+	}
+
+	cells := tableCells(a.resultTable)
+	want := [][]string{
+		{"Iron", "2,000", "$4,000"},
+		{"Copper", "3,000", "$3,000"},
+	}
+	if !slices.EqualFunc(cells, want, slices.Equal) {
+		t.Errorf("table cells %v, want %v", cells, want)
+	}
+
+	summary := labelTexts(a.resultSummary)
+	for _, text := range []string{"3 x Copper Bar", "$4,350", "3,000 x Copper", "2 x Iron Bar", "Total: $10,350"} {
+		if !slices.Contains(summary, text) {
+			t.Errorf("summary %v is missing %q", summary, text)
+		}
+	}
+}
+
+// TestTypeBadges checks the item types show with override icons in place of
+// the coloured placeholders.
+func TestTypeBadges(t *testing.T) {
+	a := newTestApp(t, nil)
+	dir := a.getIconDir()
+	icon, _ := iconFiles.ReadFile("icons/item.svg")
+	os.MkdirAll(dir, 0o755)
+	os.WriteFile(filepath.Join(dir, "copper.svg"), icon, 0o644)
+	a.icons.SetOverrideDir(dir)
+	addTestOrder(t, a, "Battery", "1")
+	test.Tap(findButton(t, a, "Calculate"))
+
+	badges := make(map[string]fyne.ThemeColorName)
+	rows, _ := a.resultTable.Length()
+	for row := range rows {
+		cell := a.resultTable.CreateCell().(*IconLabel)
+		a.resultTable.UpdateCell(widget.TableCellID{Row: row, Col: 0}, cell)
+		if cell.badge.Visible() {
+			badges[cell.label.Text] = cell.colorName
+		}
+	}
+	if want := map[string]fyne.ThemeColorName{"Copper": ColorNameOre, "Copper Bar": ColorNameAlloy, "Copper Wire": ColorNameItem}; !maps.Equal(badges, want) {
+		t.Errorf("result badges %v, want %v", badges, want)
+	}
+
+	badges = make(map[string]fyne.ThemeColorName)
+	for _, o := range test.LaidOutObjects(a.resultSummary) {
+		if label, ok := o.(*IconLabel); ok {
+			badges[label.label.Text] = label.colorName
+		}
+	}
+	if want := map[string]fyne.ThemeColorName{"10 x Copper Bar": ColorNameAlloy, "2 x Copper Wire": ColorNameItem}; !maps.Equal(badges, want) {
+		t.Errorf("summary badges %v, want %v", badges, want)
+	}
+}
+
+func TestItemNames(t *testing.T) {
+	fyneApp := test.NewTempApp(t)
+	fyneApp.Preferences().SetString("language", "de")
+	a := newTestApp(t, fyneApp)
+	t.Cleanup(func() {
+		setLanguage("en")
+		setItemNames(a.source)
+	})
+	addTestOrder(t, a, "Copper Bar", "3")
+	test.Tap(findButton(t, a, tr("Calculate")))
+
+	if cells := tableCells(a.resultTable); len(cells) != 1 || cells[0][0] != "Kupfer" {
+		t.Errorf("table cells %v", cells)
+	}
+	if orders := a.getOrders(); orders[0].Item.Name != "Copper Bar" {
+		t.Errorf("orders %v", orders)
+	}
+}
+
+func TestOrderAmount(t *testing.T) {
+	a := newTestApp(t, nil)
+	order := addTestOrder(t, a, "Robot", "1.5K")
+	if order.amount != 1500 {
+		t.Errorf("amount %d, want 1500", order.amount)
+	}
+
+	test.Tap(order.renderer.increment)
+	if order.amount != 1501 || order.renderer.amount.Text != "1501" {
+		t.Errorf("amount %d shown as %q after increment, want 1501", order.amount, order.renderer.amount.Text)
+	}
+
+	for _, input := range []string{"lots", "0e400", "0"} {
+		typeOver(order.renderer.amount, input)
+		if order.renderer.amount.Validate() == nil {
+			t.Errorf("expected a validation error for %q", input)
+		}
+		if order.amount != 1 {
+			t.Errorf("amount %d for %q, want 1", order.amount, input)
+		}
+	}
+}
+
+func TestRemoveOrderUndo(t *testing.T) {
+	a := newTestApp(t, nil)
+	addTestOrder(t, a, "Copper Bar", "3")
+	order := addTestOrder(t, a, "Iron Bar", "2")
+
+	test.Tap(order.renderer.remove)
+	if orders := a.getOrders(); len(orders) != 1 || orders[0].Item.Name != "Copper Bar" {
+		t.Fatalf("orders after remove %v", orders)
+	}
+	a.undo()
+	if orders := a.getOrders(); len(orders) != 2 || orders[1].Item.Name != "Iron Bar" || orders[1].Amount != 2 {
+		t.Fatalf("orders after undo %v", orders)
+	}
+	a.redo()
+	if orders := a.getOrders(); len(orders) != 1 {
+		t.Fatalf("orders after redo %v", orders)
+	}
+}
+
+func TestShortDragSnapsBack(t *testing.T) {
+	a := newTestApp(t, nil)
+	addTestOrder(t, a, "Copper Bar", "3")
+	order := addTestOrder(t, a, "Iron Bar", "2")
+	position := order.Position()
+
+	order.renderer.handle.Dragged(&fyne.DragEvent{Dragged: fyne.Delta{DY: order.Size().Height / 3}})
+	order.renderer.handle.DragEnd()
+	if order.Position() != position {
+		t.Errorf("order dropped at %v, want %v", order.Position(), position)
+	}
+	if orders := a.getOrders(); orders[1].Item.Name != "Iron Bar" {
+		t.Errorf("orders after short drag %v", orders)
+	}
+}
+
+func TestResultFilter(t *testing.T) {
+	a := newTestApp(t, nil)
+	addTestOrder(t, a, "Copper Bar", "3")
+	addTestOrder(t, a, "Iron Bar", "2")
+	test.Tap(findButton(t, a, "Calculate"))
+
+	test.Type(a.resultFilter, "cop")
+	if cells := tableCells(a.resultTable); len(cells) != 1 || cells[0][0] != "Copper" {
+		t.Errorf("filtered cells %v", cells)
+	}
+	a.resultFilter.SetText("")
+	if cells := tableCells(a.resultTable); len(cells) != 2 {
+		t.Errorf("unfiltered cells %v", cells)
+	}
+}
+
+func TestWideLayout(t *testing.T) {
+	a := newTestApp(t, nil)
+	if !a.bonusButton.Visible() || a.ordersSplit != nil {
+		t.Error("narrow window should have a bonuses button and no split")
+	}
+	a.mainWindow.Resize(fyne.NewSize(1000, 700))
+	if a.bonusButton.Visible() || a.ordersSplit == nil {
+		t.Error("wide window should show the bonuses beside the orders")
+	}
+	a.mainWindow.Resize(fyne.NewSize(400, 800))
+	if !a.bonusButton.Visible() {
+		t.Error("narrow window should have a bonuses button again")
+	}
+}
+
+func TestBonusesRoundTrip(t *testing.T) {
+	a := newTestApp(t, nil)
+	typeOver(a.bonusForm.smeltValEntry, "1.5")
+	typeOver(a.bonusForm.dormsEntry, "2.25")
+	test.Tap(a.bonusForm.craftEfficiency)
+	want := Bonuses{
+		CraftingEfficiency: true,
+		CraftValBonus:      1,
+		SmeltValBonus:      1.5,
+		UnderforgeBonus:    1,
+		DormsBonus:         2.25,
+	}
+	if a.Bonuses != want {
+		t.Fatalf("bonuses %+v, want %+v", a.Bonuses, want)
+	}
+	a.onStopped()
+
+	restarted := newTestApp(t, a.app)
+	if restarted.Bonuses != want {
+		t.Errorf("loaded bonuses %+v, want %+v", restarted.Bonuses, want)
+	}
+	if text := restarted.bonusForm.smeltValEntry.Text; text != "1.50" {
+		t.Errorf("smelt value shown as %q, want 1.50", text)
+	}
+	if !restarted.bonusForm.craftEfficiency.Checked {
+		t.Error("crafting efficiency should be checked")
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+)
+
+// backupVersion is the layout of settings backups this build writes, newer
+// ones are refused rather than half imported.
+const backupVersion = 1
+
+// settingsBackup is everything a user sets up, to move between devices or
+// restore after reinstalling. The window size and split positions belong to
+// the device so they're left out.
+type settingsBackup struct {
+	Version       int                `json:"version"`
+	App           string             `json:"app"`
+	Bonuses       *Bonuses           `json:"bonuses,omitempty"`
+	BonusProfiles map[string]Bonuses `json:"bonusProfiles,omitempty"`
+	Plans         map[string]string  `json:"plans,omitempty"`
+	Overrides     json.RawMessage    `json:"overrides,omitempty"` // game data, migrated like any other
+	SimOres       string             `json:"simOres,omitempty"`
+	Display       *displaySettings   `json:"display,omitempty"`
+	overrides     *jsonGameData
+}
+
+type displaySettings struct {
+	Language     string       `json:"language"`
+	NumberFormat NumberFormat `json:"numberFormat"`
+	ThemeVariant ThemeVariant `json:"themeVariant"`
+	AccentColor  string       `json:"accentColor"`
+	HistoryDepth int          `json:"historyDepth"`
+}
+
+func (a *App) getBackup() settingsBackup {
+	prefs := a.app.Preferences()
+	backup := settingsBackup{
+		Version:       backupVersion,
+		App:           appID,
+		Bonuses:       &a.Bonuses,
+		BonusProfiles: a.bonusProfiles,
+		Plans:         a.plans,
+		SimOres:       prefs.String("simOres"),
+		Display: &displaySettings{
+			Language:     prefs.String("language"),
+			NumberFormat: numberFormat,
+			ThemeVariant: ThemeVariant(prefs.IntWithFallback("themeVariant", int(SystemVariant))),
+			AccentColor:  prefs.String("accentColor"),
+			HistoryDepth: a.history.depth,
+		},
+	}
+	if len(a.overrides.Ores)+len(a.overrides.Alloys)+len(a.overrides.Items) > 0 {
+		backup.Overrides, _ = json.Marshal(a.overrides)
+	}
+	return backup
+}
+
+func writeBackup(w io.Writer, backup settingsBackup) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(backup)
+}
+
+func checkBonuses(name string, b Bonuses) error {
+	for _, multiplier := range []float64{b.CraftValBonus, b.SmeltValBonus, b.UnderforgeBonus, b.DormsBonus} {
+		if multiplier <= 0 || math.IsInf(multiplier, 0) {
+			return fmt.Errorf("%s bonuses need multipliers above 0", name)
+		}
+	}
+	return nil
+}
+
+// parseBackup reads a settings backup, rejecting anything that isn't one or
+// has settings this build can't use.
+func parseBackup(b []byte) (*settingsBackup, error) {
+	var backup settingsBackup
+	if err := json.Unmarshal(b, &backup); err != nil {
+		return nil, fmt.Errorf("invalid settings backup: %w", err)
+	}
+	if backup.App != appID || backup.Version < 1 {
+		return nil, errors.New("not a settings backup")
+	}
+	if backup.Version > backupVersion {
+		return nil, fmt.Errorf("settings backup version %d is newer than this app supports (%d), try updating", backup.Version, backupVersion)
+	}
+
+	if backup.Bonuses != nil {
+		if err := checkBonuses("current", *backup.Bonuses); err != nil {
+			return nil, err
+		}
+	}
+	for name, bonuses := range backup.BonusProfiles {
+		if err := checkBonuses(name, bonuses); err != nil {
+			return nil, err
+		}
+	}
+	if d := backup.Display; d != nil {
+		switch {
+		case d.Language != "" && languageNames[d.Language] == "":
+			return nil, fmt.Errorf("unknown language: %s", d.Language)
+		case d.NumberFormat < FullNumbers || d.NumberFormat > ScientificNumbers:
+			return nil, fmt.Errorf("unknown number format: %d", d.NumberFormat)
+		case d.ThemeVariant < SystemVariant || d.ThemeVariant > DarkVariant:
+			return nil, fmt.Errorf("unknown theme: %d", d.ThemeVariant)
+		case d.AccentColor != "" && !slices.Contains(theme.PrimaryColorNames(), d.AccentColor):
+			return nil, fmt.Errorf("unknown accent colour: %s", d.AccentColor)
+		case d.HistoryDepth < 1:
+			return nil, errors.New("undo depth has to be at least 1")
+		}
+	}
+	if len(backup.Overrides) > 0 && string(backup.Overrides) != "null" {
+		overrides, err := parseData(backup.Overrides)
+		if err != nil {
+			return nil, err
+		}
+		backup.overrides = overrides
+	}
+	return &backup, nil
+}
+
+// Report lists what importing the backup changes
+func (backup *settingsBackup) Report() string {
+	lines := make([]string, 0)
+	if backup.Bonuses != nil {
+		lines = append(lines, tr("Bonuses are replaced"))
+	}
+	if backup.Display != nil {
+		lines = append(lines, tr("Display settings are replaced"))
+	}
+	if len(backup.BonusProfiles) > 0 {
+		lines = append(lines, fmt.Sprintf(tr("Bonus profiles added or replaced: %s"),
+			strings.Join(slices.Sorted(maps.Keys(backup.BonusProfiles)), ", ")))
+	}
+	if len(backup.Plans) > 0 {
+		lines = append(lines, fmt.Sprintf(tr("Plans added or replaced: %s"),
+			strings.Join(slices.Sorted(maps.Keys(backup.Plans)), ", ")))
+	}
+	if backup.overrides != nil {
+		count := len(backup.overrides.Ores) + len(backup.overrides.Alloys) + len(backup.overrides.Items)
+		lines = append(lines, fmt.Sprintf(tr("Game data edits added or replaced: %d"), count))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// importBackup merges a backup into the current settings, the bonuses and
+// display settings are replaced, profiles, plans and game data edits with
+// the same name are overwritten and the rest are kept.
+func (a *App) importBackup(backup *settingsBackup) error {
+	if backup.overrides != nil {
+		overrides := applyOverrides(a.overrides, backup.overrides)
+		if err := getNewDataErrors(applyOverrides(a.source, a.overrides), applyOverrides(a.source, overrides)); err != nil {
+			return err
+		}
+		a.overrides = overrides
+		if err := a.saveOverrides(); err != nil {
+			return err
+		}
+		a.setGameData()
+	}
+
+	maps.Copy(a.bonusProfiles, backup.BonusProfiles)
+	a.saveBonusProfiles()
+	maps.Copy(a.plans, backup.Plans)
+	a.savePlans()
+	if backup.SimOres != "" {
+		a.app.Preferences().SetString("simOres", backup.SimOres)
+	}
+
+	if d := backup.Display; d != nil {
+		prefs := a.app.Preferences()
+		prefs.SetString("language", d.Language)
+		numberFormat = d.NumberFormat
+		prefs.SetInt("numberFormat", int(d.NumberFormat))
+		prefs.SetInt("themeVariant", int(d.ThemeVariant))
+		prefs.SetString("accentColor", d.AccentColor)
+		a.applyTheme()
+		a.history.SetDepth(d.HistoryDepth)
+		prefs.SetInt("historyDepth", d.HistoryDepth)
+	}
+	if backup.Bonuses != nil {
+		previous := a.Bonuses
+		a.setBonuses(*backup.Bonuses)
+		a.bonusesChanged(previous)
+	}
+	a.displayResults(a.results)
+	return nil
+}
+
+func (a *App) exportSettingsHandler() {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.mainWindow)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+		if err := writeBackup(writer, a.getBackup()); err != nil {
+			dialog.ShowError(err, a.mainWindow)
+		}
+	}, a.mainWindow)
+	saveDialog.SetFileName("idle-planet-calc-settings.json")
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	saveDialog.Show()
+}
+
+func (a *App) importSettingsHandler() {
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.mainWindow)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+		b, err := io.ReadAll(reader)
+		if err != nil {
+			dialog.ShowError(err, a.mainWindow)
+			return
+		}
+		backup, err := parseBackup(b)
+		if err != nil {
+			dialog.ShowError(err, a.mainWindow)
+			return
+		}
+		report := backup.Report()
+		if report == "" {
+			dialog.ShowInformation(tr("Import settings"), tr("Nothing to import"), a.mainWindow)
+			return
+		}
+		language := a.app.Preferences().String("language")
+		dialog.ShowConfirm(tr("Import settings"), report, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := a.importBackup(backup); err != nil {
+				dialog.ShowError(err, a.mainWindow)
+				return
+			}
+			if backup.Display != nil && backup.Display.Language != language {
+				dialog.ShowInformation(tr("Language"), tr("The new language is used next time the app starts"), a.mainWindow)
+			}
+		}, a.mainWindow)
+	}, a.mainWindow)
+	openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	openDialog.Show()
+}
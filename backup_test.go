@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBackupRoundTrip(t *testing.T) {
+	t.Cleanup(func() { numberFormat = FullNumbers })
+	a := newTestApp(t, nil)
+	bonuses := Bonuses{SmeltingEfficiency: true, CraftValBonus: 1.5, SmeltValBonus: 2, UnderforgeBonus: 1.25, DormsBonus: 1.125}
+	a.setBonuses(bonuses)
+	a.bonusProfiles["Late game"] = NewBonuses()
+	a.plans["Reactors"] = "3 Fusion Reactor"
+	a.app.Preferences().SetInt("themeVariant", int(DarkVariant))
+	a.app.Preferences().SetString("accentColor", "purple")
+	numberFormat = SuffixNumbers
+	_, index, _ := a.source.find("Iron Bar")
+	ironBar := a.source.Alloys[index]
+	ironBar.Value = 3500
+	a.overrides.put(Alloy, ironBar)
+
+	var buf bytes.Buffer
+	if err := writeBackup(&buf, a.getBackup()); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := newTestApp(t, nil)
+	restored.plans["Robots"] = "10 Robot"
+	backup, err := parseBackup(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.importBackup(backup); err != nil {
+		t.Fatal(err)
+	}
+
+	if restored.Bonuses != bonuses {
+		t.Errorf("bonuses %+v, want %+v", restored.Bonuses, bonuses)
+	}
+	if text := restored.bonusForm.craftValEntry.Text; text != "1.50" {
+		t.Errorf("craft value shown as %q, want 1.50", text)
+	}
+	if _, found := restored.bonusProfiles["Late game"]; !found {
+		t.Error("bonus profile missing")
+	}
+	if restored.plans["Reactors"] != "3 Fusion Reactor" || restored.plans["Robots"] != "10 Robot" {
+		t.Errorf("plans %v should have both the imported and existing plan", restored.plans)
+	}
+	if restored.data["Iron Bar"].Value != 3500 {
+		t.Errorf("Iron Bar value %d, want the edited 3500", restored.data["Iron Bar"].Value)
+	}
+	prefs := restored.app.Preferences()
+	if prefs.Int("themeVariant") != int(DarkVariant) || prefs.String("accentColor") != "purple" {
+		t.Errorf("theme %d with accent %q, want dark and purple", prefs.Int("themeVariant"), prefs.String("accentColor"))
+	}
+	if numberFormat != SuffixNumbers || prefs.Int("numberFormat") != int(SuffixNumbers) {
+		t.Errorf("number format %v, want %v", numberFormat, SuffixNumbers)
+	}
+}
+
+func TestParseBackupErrors(t *testing.T) {
+	for _, test := range []struct {
+		backup string
+		err    string
+	}{
+		{`[]`, "invalid settings backup"},
+		{`{"ores": []}`, "not a settings backup"},
+		{`{"version": 2, "app": "` + appID + `"}`, "newer than this app supports"},
+		{`{"version": 1, "app": "` + appID + `", "bonuses": {"craftValBonus": 1}}`, "current bonuses"},
+		{`{"version": 1, "app": "` + appID + `", "bonusProfiles": {"Broken": {"craftValBonus": -1}}}`, "Broken bonuses"},
+		{`{"version": 1, "app": "` + appID + `", "display": {"language": "xx", "historyDepth": 50}}`, "unknown language"},
+		{`{"version": 1, "app": "` + appID + `", "display": {"themeVariant": 7, "historyDepth": 50}}`, "unknown theme"},
+		{`{"version": 1, "app": "` + appID + `", "display": {"accentColor": "mauve", "historyDepth": 50}}`, "unknown accent"},
+		{`{"version": 1, "app": "` + appID + `", "display": {}}`, "undo depth"},
+		{`{"version": 1, "app": "` + appID + `", "overrides": {"schemaVersion": 9}}`, "game data schema"},
+	} {
+		if _, err := parseBackup([]byte(test.backup)); err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: got error %v, want %q", test.backup, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import "sync"
+
+// bomCacheSize is how many sets of bonuses to keep bills of materials for,
+// the server sees whatever bonuses its clients send.
+const bomCacheSize = 16
+
+// bomCache memoises the flattened bill of materials for one of each item,
+// per set of bonuses. Items are cached by name, so each set of game data
+// gets its own cache, handed to its items by buildGameData.
+type bomCache struct {
+	mutex sync.Mutex
+	boms  map[Bonuses]map[string][]Ingredient
+}
+
+func newBOMCache() *bomCache {
+	return &bomCache{
+		boms: make(map[Bonuses]map[string][]Ingredient),
+	}
+}
+
+func (c *bomCache) clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	clear(c.boms)
+}
+
+// get returns everything that goes into one of item, each ingredient once
+// with its total amount. The slice is shared, callers mustn't change it.
+// Items that weren't loaded from game data have no cache and are expanded
+// every time.
+func (c *bomCache) get(b Bonuses, item GameItem) []Ingredient {
+	if c == nil {
+		return expandIngredients(b, item, make(map[string][]Ingredient))
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	items, found := c.boms[b]
+	if !found {
+		if len(c.boms) >= bomCacheSize {
+			clear(c.boms)
+		}
+		items = make(map[string][]Ingredient)
+		c.boms[b] = items
+	}
+	return expandIngredients(b, item, items)
+}
+
+func expandIngredients(b Bonuses, item GameItem, items map[string][]Ingredient) []Ingredient {
+	if bom, found := items[item.Name]; found {
+		return bom
+	}
+	bom := make([]Ingredient, 0)
+	index := make(map[string]int)
+	add := func(ingredient GameItem, value int, amount int) {
+		if i, found := index[ingredient.Name]; found {
+			bom[i].Amount += amount
+			return
+		}
+		index[ingredient.Name] = len(bom)
+		bom = append(bom, Ingredient{Item: ingredient, Value: value, Amount: amount})
+	}
+	for _, i := range item.Ingredients {
+		amount := b.getBonusedMaterialAmount(item.Type, i.Amount)
+		add(i.Item, b.getBonusedValue(i.Item.Type, i.Item.Value), amount)
+		for _, sub := range expandIngredients(b, i.Item, items) {
+			add(sub.Item, sub.Value, sub.Amount*amount)
+		}
+	}
+	items[item.Name] = bom
+	return bom
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/validation"
+	"fyne.io/fyne/v2/widget"
+)
+
+func parseBonus(input string) float64 {
+	val, err := strconv.ParseFloat(input, 32)
+	if err != nil || val == float64(0) || math.IsNaN(val) || math.IsInf(val, 0) {
+		val = 1.0
+	}
+	return val
+}
+
+func formatBonus(val float64) string {
+	return fmt.Sprintf("%.2f", val)
+}
+
+// BonusForm edits a set of bonuses in place, calling onChanged with the
+// previous values whenever the user changes one of them.
+type BonusForm struct {
+	bonuses         *Bonuses
+	onChanged       func(previous Bonuses)
+	updating        bool
+	craftEfficiency *widget.Check
+	smeltEfficiency *widget.Check
+	craftValEntry   *shortcutEntry
+	smeltValEntry   *shortcutEntry
+	underforgeEntry *shortcutEntry
+	dormsEntry      *shortcutEntry
+	form            *widget.Form
+}
+
+func NewBonusForm(bonuses *Bonuses, onChanged func(previous Bonuses)) *BonusForm {
+	f := &BonusForm{
+		bonuses:   bonuses,
+		onChanged: onChanged,
+	}
+
+	getFormattedEntry := func(field *float64) *shortcutEntry {
+		entry := newShortcutEntry()
+		entry.Resize(entry.MinSize().AddWidthHeight(20, 0))
+		entry.Validator = validation.NewRegexp("^[0-9.]+$", tr("Numbers only please"))
+		entry.OnSubmitted = func(input string) {
+			entry.SetText(formatBonus(parseBonus(entry.Text)))
+		}
+		entry.OnChanged = func(input string) {
+			if f.updating {
+				return
+			}
+			previous := *f.bonuses
+			*field = parseBonus(input)
+			f.changed(previous)
+		}
+		return entry
+	}
+	getCheck := func(field *bool) *widget.Check {
+		return widget.NewCheck("", func(input bool) {
+			if f.updating {
+				return
+			}
+			previous := *f.bonuses
+			*field = input
+			f.changed(previous)
+		})
+	}
+
+	f.craftEfficiency = getCheck(&bonuses.CraftingEfficiency)
+	f.smeltEfficiency = getCheck(&bonuses.SmeltingEfficiency)
+	f.craftValEntry = getFormattedEntry(&bonuses.CraftValBonus)
+	f.smeltValEntry = getFormattedEntry(&bonuses.SmeltValBonus)
+	f.underforgeEntry = getFormattedEntry(&bonuses.UnderforgeBonus)
+	f.dormsEntry = getFormattedEntry(&bonuses.DormsBonus)
+	f.Refresh()
+
+	f.form = widget.NewForm(
+		widget.NewFormItem(tr("Craft Eff."), f.craftEfficiency),
+		widget.NewFormItem(tr("Smelt Eff."), f.smeltEfficiency),
+		widget.NewFormItem(tr("Smelt Value"), f.smeltValEntry),
+		widget.NewFormItem(tr("Craft Value"), f.craftValEntry),
+		widget.NewFormItem(tr("Underforge"), f.underforgeEntry),
+		widget.NewFormItem(tr("Dorms"), f.dormsEntry),
+	)
+	return f
+}
+
+func (f *BonusForm) changed(previous Bonuses) {
+	if f.onChanged == nil {
+		return
+	}
+	f.onChanged(previous)
+}
+
+// Refresh updates the form to match the bonuses without calling onChanged or
+// rounding the bonuses to what's shown
+func (f *BonusForm) Refresh() {
+	f.updating = true
+	defer func() { f.updating = false }()
+
+	bonuses := *f.bonuses
+	f.craftEfficiency.SetChecked(bonuses.CraftingEfficiency)
+	f.smeltEfficiency.SetChecked(bonuses.SmeltingEfficiency)
+	f.craftValEntry.SetText(formatBonus(bonuses.CraftValBonus))
+	f.smeltValEntry.SetText(formatBonus(bonuses.SmeltValBonus))
+	f.underforgeEntry.SetText(formatBonus(bonuses.UnderforgeBonus))
+	f.dormsEntry.SetText(formatBonus(bonuses.DormsBonus))
+}
+
+func (f *BonusForm) Container() *fyne.Container {
+	return container.NewVBox(f.form)
+}
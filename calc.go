@@ -0,0 +1,128 @@
+package main
+
+import (
+	"maps"
+	"math"
+	"slices"
+)
+
+func NewBonuses() Bonuses {
+	return Bonuses{
+		CraftValBonus:   1.0,
+		SmeltValBonus:   1.0,
+		UnderforgeBonus: 1.0,
+		DormsBonus:      1.0,
+	}
+}
+
+// calculate returns the sorted bill of materials and the per order summary,
+// every front end goes through here so their results match.
+func (b Bonuses) calculate(orders []Ingredient) ([]Ingredient, []ResultItem) {
+	return sortResults(b.calculateIngredients(orders)), b.getDisplayResults(orders)
+}
+
+func (b Bonuses) getDisplayResults(order []Ingredient) []ResultItem {
+	result := make([]ResultItem, 0)
+	for _, o := range order {
+		order := ResultItem{
+			Amount:      o.Amount,
+			Name:        o.Item.Name,
+			Group:       o.Group,
+			Value:       b.getBonusedValue(o.Item.Type, o.Item.Value) * o.Amount,
+			Ingredients: make([]ResultItem, 0),
+		}
+
+		for _, i := range o.Item.Ingredients {
+			order.Ingredients = append(order.Ingredients, ResultItem{
+				Amount: b.getBonusedMaterialAmount(o.Item.Type, i.Amount) * o.Amount,
+				Name:   i.Item.Name,
+				Value:  b.getBonusedValue(i.Item.Type, i.Value) * o.Amount,
+			})
+		}
+		result = append(result, order)
+	}
+	return result
+}
+
+func sortResults(ingredients map[string]Ingredient) []Ingredient {
+	result := slices.Collect(maps.Values(ingredients))
+	slices.SortFunc(result, func(a, b Ingredient) int {
+		if a.Item.Type != b.Item.Type {
+			if a.Item.Type > b.Item.Type {
+				return -1
+			} else {
+				return 1
+			}
+		}
+		if a.Value != b.Value {
+			if a.Value < b.Value {
+				return 1
+			} else {
+				return -1
+			}
+		}
+		return 0
+	})
+	return result
+}
+
+func (b Bonuses) calculateIngredients(order []Ingredient) (bill map[string]Ingredient) {
+	bill = make(map[string]Ingredient)
+	for _, o := range order {
+		ingredients := b.getIngredients(o.Item)
+		for _, i := range ingredients {
+			value := i.Item.Value * i.Amount * o.Amount
+			amount := i.Amount * o.Amount
+			if item, found := bill[i.Item.Name]; !found {
+				bill[i.Item.Name] = Ingredient{Item: i.Item, Value: value, Amount: amount}
+			} else {
+				item.Value += value
+				item.Amount += amount
+				bill[i.Item.Name] = item
+			}
+		}
+	}
+	return
+}
+
+func (b Bonuses) getBonusedMaterialAmount(itemType ItemType, value int) int {
+	roomBonus := float64(1)
+	projectBonus := float64(1)
+	amount := float64(value)
+
+	if itemType == Item {
+		roomBonus = b.DormsBonus
+		if b.CraftingEfficiency {
+			projectBonus = 1.2
+		}
+	} else {
+		roomBonus = b.UnderforgeBonus
+		if b.SmeltingEfficiency {
+			projectBonus = 1.2
+		}
+	}
+
+	basePrice := amount - (amount * (roomBonus - 1))
+	smeltBonus := basePrice * (projectBonus - 1)
+	if smeltBonus < 1 {
+		smeltBonus = math.Round(smeltBonus)
+	}
+	return int(math.Round(basePrice - smeltBonus))
+}
+
+func (b Bonuses) getBonusedValue(itemType ItemType, value int) int {
+	var projectBonus float64
+	amount := float64(value)
+	if itemType == Item {
+		projectBonus = b.CraftValBonus
+	} else {
+		projectBonus = b.SmeltValBonus
+	}
+	return int(math.Round(amount * projectBonus))
+}
+
+// getIngredients is everything that goes into one of item, expanded once per
+// set of bonuses and then served from its game data's cache.
+func (b Bonuses) getIngredients(item GameItem) []Ingredient {
+	return item.boms.get(b, item)
+}
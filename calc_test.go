@@ -0,0 +1,208 @@
+package main
+
+import (
+	"maps"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// testBonuses generates bonuses for property tests, the value multipliers go
+// from a half up to five, well past what the game allows. Room bonuses stay
+// under two, where the rooms would take away the whole recipe.
+type testBonuses struct {
+	Bonuses
+}
+
+func (testBonuses) Generate(r *rand.Rand, size int) reflect.Value {
+	multiplier := func(most float64) float64 {
+		return math.Round((0.5+r.Float64()*(most-0.5))*100) / 100
+	}
+	return reflect.ValueOf(testBonuses{Bonuses{
+		CraftingEfficiency: r.Intn(2) == 0,
+		SmeltingEfficiency: r.Intn(2) == 0,
+		CraftValBonus:      multiplier(5),
+		SmeltValBonus:      multiplier(5),
+		UnderforgeBonus:    min(multiplier(2), 1.99),
+		DormsBonus:         min(multiplier(2), 1.99),
+	}})
+}
+
+// countIngredients walks the recipes without the cache
+func countIngredients(b Bonuses, item GameItem, amount int, counts map[string]int) {
+	for _, i := range item.Ingredients {
+		ingredientAmount := b.getBonusedMaterialAmount(item.Type, i.Amount) * amount
+		counts[i.Item.Name] += ingredientAmount
+		countIngredients(b, i.Item, ingredientAmount, counts)
+	}
+}
+
+func TestIngredients(t *testing.T) {
+	data := getGameData(loadData())
+	for _, bonuses := range []Bonuses{NewBonuses(), {
+		CraftingEfficiency: true,
+		SmeltingEfficiency: true,
+		CraftValBonus:      2,
+		SmeltValBonus:      1.5,
+		UnderforgeBonus:    1.1,
+		DormsBonus:         1.2,
+	}} {
+		for name, item := range data {
+			want := make(map[string]int)
+			countIngredients(bonuses, item, 1, want)
+			got := make(map[string]int)
+			for _, i := range bonuses.getIngredients(item) {
+				if _, found := got[i.Item.Name]; found {
+					t.Errorf("%s lists %s twice", name, i.Item.Name)
+				}
+				got[i.Item.Name] = i.Amount
+			}
+			if !maps.Equal(got, want) {
+				t.Errorf("%s with %+v: %v, want %v", name, bonuses, got, want)
+			}
+		}
+	}
+}
+
+// TestBillScales checks the bill for N of an item is N times the bill for
+// one, bonuses are rounded per recipe so they can't drift with the amount.
+func TestBillScales(t *testing.T) {
+	data := getGameData(loadData())
+	names := getItemList(data)
+	scales := func(bonuses testBonuses, index uint8, amount uint16) bool {
+		item := data[names[int(index)%len(names)]]
+		n := int(amount) + 1
+		one := bonuses.calculateIngredients([]Ingredient{{Item: item, Amount: 1}})
+		many := bonuses.calculateIngredients([]Ingredient{{Item: item, Amount: n}})
+		return maps.EqualFunc(one, many, func(x, y Ingredient) bool {
+			return x.Amount*n == y.Amount && x.Value*n == y.Value
+		})
+	}
+	if err := quick.Check(scales, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// rawIngredients counts everything that goes into amount of item straight
+// from the recipes.
+func rawIngredients(item GameItem, amount int, counts map[string]int) {
+	for _, i := range item.Ingredients {
+		counts[i.Item.Name] += i.Amount * amount
+		rawIngredients(i.Item, i.Amount*amount, counts)
+	}
+}
+
+func TestNoBonusesUseRecipes(t *testing.T) {
+	bonuses := NewBonuses()
+	unchanged := func(itemType uint8, n uint32) bool {
+		return bonuses.getBonusedMaterialAmount(ItemType(itemType%3), int(n)) == int(n) &&
+			bonuses.getBonusedValue(ItemType(itemType%3), int(n)) == int(n)
+	}
+	if err := quick.Check(unchanged, nil); err != nil {
+		t.Error(err)
+	}
+
+	for name, item := range getGameData(loadData()) {
+		want := make(map[string]int)
+		rawIngredients(item, 1, want)
+		for _, i := range bonuses.getIngredients(item) {
+			if want[i.Item.Name] != i.Amount {
+				t.Errorf("%s needs %d %s, the recipes add up to %d", name, i.Amount, i.Item.Name, want[i.Item.Name])
+			}
+		}
+	}
+}
+
+func TestOreNeverNegative(t *testing.T) {
+	data := getGameData(loadData())
+	names := getItemList(data)
+	positive := func(bonuses testBonuses, index uint8, amount uint16) bool {
+		item := data[names[int(index)%len(names)]]
+		results, _ := bonuses.calculate([]Ingredient{{Item: item, Amount: int(amount) + 1}})
+		for _, r := range results {
+			if r.Item.Type == Ore && (r.Amount < 0 || r.Value < 0) {
+				t.Logf("%d %s for %s with %+v", r.Amount, r.Item.Name, item.Name, bonuses)
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(positive, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
+
+// changedCopperBar is the built in game data with Copper Bars needing half
+// the Copper.
+func changedCopperBar() *jsonGameData {
+	data := loadData()
+	_, index, _ := data.find("Copper Bar")
+	data.Alloys[index].Ingredients = []jsonIngredient{{Name: "Copper", Amount: 500}}
+	return data
+}
+
+func TestIngredientsPerDataset(t *testing.T) {
+	before := getGameData(loadData())
+	after := getGameData(changedCopperBar())
+	bonuses := NewBonuses()
+	for _, test := range []struct {
+		data map[string]GameItem
+		want int
+	}{{before, 1000}, {after, 500}, {before, 1000}} {
+		if amount := bonuses.getIngredients(test.data["Copper Bar"])[0].Amount; amount != test.want {
+			t.Errorf("Copper Bar needs %d Copper, want %d", amount, test.want)
+		}
+	}
+}
+
+func TestHasCraftTimes(t *testing.T) {
+	bonuses := NewBonuses()
+	if bonuses.hasCraftTimes(getGameData(loadData())) {
+		t.Error("craft times found in the built in data, the cash goal can offer fewest craft hours")
+	}
+	data := loadData()
+	_, index, _ := data.find("Copper Bar")
+	data.Alloys[index].Time = 60
+	if !bonuses.hasCraftTimes(getGameData(data)) {
+		t.Error("no craft times with a timed Copper Bar")
+	}
+}
+
+// largePlan orders a thousand of every item in the inventory
+func largePlan(data map[string]GameItem) []Ingredient {
+	orders := make([]Ingredient, 0)
+	for _, name := range getItemList(data) {
+		orders = append(orders, Ingredient{Item: data[name], Amount: 1000})
+	}
+	return orders
+}
+
+func BenchmarkCalculate(b *testing.B) {
+	orders := largePlan(getGameData(loadData()))
+	bonuses := NewBonuses()
+	for b.Loop() {
+		bonuses.calculate(orders)
+	}
+}
+
+func BenchmarkCalculateUncached(b *testing.B) {
+	orders := largePlan(getGameData(loadData()))
+	bonuses := NewBonuses()
+	for b.Loop() {
+		orders[0].Item.boms.clear()
+		bonuses.calculate(orders)
+	}
+}
+
+// BenchmarkPlanCandidates is the optimiser's inner loop, costing every item
+// under a new set of bonuses.
+func BenchmarkPlanCandidates(b *testing.B) {
+	data := getGameData(loadData())
+	names := getItemList(data)
+	bonuses := NewBonuses()
+	for b.Loop() {
+		bonuses.getPlanCandidates(LeastOre, data, names)
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+type comparisonRow struct {
+	Name            string
+	CurrentAmount   int
+	ProjectedAmount int
+	CurrentValue    int
+	ProjectedValue  int
+}
+
+type comparison struct {
+	Rows           []comparisonRow
+	CurrentTotal   int
+	ProjectedTotal int
+}
+
+func formatDelta(val int) string {
+	if val > 0 {
+		return "+" + formatNumber(val)
+	}
+	return formatNumber(val)
+}
+
+func compareBonuses(current, projected Bonuses, orders []Ingredient) comparison {
+	return compareOrders(current, orders, projected, orders)
+}
+
+// compareOrders works out both sets of orders under their bonuses, rows
+// follow the current bill of materials with anything only the projected
+// one needs on the end.
+func compareOrders(current Bonuses, currentOrders []Ingredient, projected Bonuses, projectedOrders []Ingredient) comparison {
+	result := comparison{Rows: make([]comparisonRow, 0)}
+	currentBill := sortResults(current.calculateIngredients(currentOrders))
+	projectedBill := projected.calculateIngredients(projectedOrders)
+
+	for _, i := range currentBill {
+		row := comparisonRow{
+			Name:          i.Item.Name,
+			CurrentAmount: i.Amount,
+			CurrentValue:  i.Value,
+		}
+		if p, found := projectedBill[i.Item.Name]; found {
+			row.ProjectedAmount = p.Amount
+			row.ProjectedValue = p.Value
+			delete(projectedBill, i.Item.Name)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	for _, p := range sortResults(projectedBill) {
+		result.Rows = append(result.Rows, comparisonRow{
+			Name:            p.Item.Name,
+			ProjectedAmount: p.Amount,
+			ProjectedValue:  p.Value,
+		})
+	}
+
+	for _, o := range current.getDisplayResults(currentOrders) {
+		result.CurrentTotal += o.Value
+	}
+	for _, o := range projected.getDisplayResults(projectedOrders) {
+		result.ProjectedTotal += o.Value
+	}
+	return result
+}
+
+func (a *App) saveBonusProfiles() {
+	profiles, _ := json.Marshal(a.bonusProfiles)
+	a.app.Preferences().SetString("bonusProfiles", string(profiles))
+}
+
+func (a *App) loadBonusProfiles() {
+	a.bonusProfiles = make(map[string]Bonuses)
+	json.Unmarshal([]byte(a.app.Preferences().String("bonusProfiles")), &a.bonusProfiles)
+}
+
+func (a *App) compareHandler() {
+	var result comparison
+	orders := a.getOrders()
+	projected := a.Bonuses
+
+	headers := []string{tr("Item"), tr("Current"), tr("Projected"), tr("Change"),
+		tr("Current value"), tr("Projected value"), tr("Change")}
+	table := widget.NewTable(
+		func() (rows int, cols int) {
+			return len(result.Rows), len(headers)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("template")
+			label.SizeName = theme.SizeNameCaptionText
+			return label
+		},
+		func(tci widget.TableCellID, co fyne.CanvasObject) {
+			row := result.Rows[tci.Row]
+			var text string
+			switch tci.Col {
+			case 0:
+				text = itemName(row.Name)
+			case 1:
+				text = formatNumber(row.CurrentAmount)
+			case 2:
+				text = formatNumber(row.ProjectedAmount)
+			case 3:
+				text = formatDelta(row.ProjectedAmount - row.CurrentAmount)
+			case 4:
+				text = formatMoney(row.CurrentValue)
+			case 5:
+				text = formatMoney(row.ProjectedValue)
+			case 6:
+				text = formatDelta(row.ProjectedValue - row.CurrentValue)
+			}
+			co.(*widget.Label).SetText(text)
+		},
+	)
+	for col, width := range []float32{120, 90, 90, 90, 130, 130, 100} {
+		table.SetColumnWidth(col, width)
+	}
+	table.ShowHeaderRow = true
+	table.CreateHeader = func() fyne.CanvasObject {
+		label := widget.NewLabel("template")
+		label.TextStyle.Bold = true
+		return label
+	}
+	table.UpdateHeader = func(id widget.TableCellID, template fyne.CanvasObject) {
+		template.(*widget.Label).SetText(headers[id.Col])
+	}
+	total := widget.NewLabel("")
+	total.Wrapping = fyne.TextWrapWord
+
+	update := func() {
+		result = compareBonuses(a.Bonuses, projected, orders)
+		table.Refresh()
+		total.SetText(fmt.Sprintf(tr("Order value: $%s → $%s (%s)"),
+			formatNumber(result.CurrentTotal),
+			formatNumber(result.ProjectedTotal),
+			formatDelta(result.ProjectedTotal-result.CurrentTotal)))
+	}
+	projectedForm := NewBonusForm(&projected, func(Bonuses) { update() })
+
+	profileSelect := widget.NewSelect(slices.Sorted(maps.Keys(a.bonusProfiles)), func(name string) {
+		if profile, found := a.bonusProfiles[name]; found {
+			projected = profile
+			projectedForm.Refresh()
+			update()
+		}
+	})
+	profileSelect.PlaceHolder = tr("Saved bonuses")
+	profileName := widget.NewEntry()
+	profileName.SetPlaceHolder(tr("Name"))
+	saveButton := widget.NewButtonWithIcon("", theme.DocumentSaveIcon(), func() {
+		if profileName.Text == "" {
+			return
+		}
+		a.bonusProfiles[profileName.Text] = projected
+		a.saveBonusProfiles()
+		profileSelect.SetOptions(slices.Sorted(maps.Keys(a.bonusProfiles)))
+		profileName.SetText("")
+	})
+	deleteButton := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		delete(a.bonusProfiles, profileSelect.Selected)
+		a.saveBonusProfiles()
+		profileSelect.ClearSelected()
+		profileSelect.SetOptions(slices.Sorted(maps.Keys(a.bonusProfiles)))
+	})
+	applyButton := widget.NewButtonWithIcon(tr("Use projected"), theme.ConfirmIcon(), func() {
+		previous := a.Bonuses
+		a.setBonuses(projected)
+		a.bonusesChanged(previous)
+		update()
+	})
+	update()
+
+	compareDialog := dialog.NewCustom(tr("Compare bonuses"), tr("Close"), container.NewBorder(
+		container.NewVBox(
+			widget.NewAccordion(widget.NewAccordionItem(tr("Projected bonuses"), container.NewVBox(
+				projectedForm.Container(),
+				container.NewBorder(nil, nil, nil, deleteButton, profileSelect),
+				container.NewBorder(nil, nil, nil, saveButton, profileName),
+				applyButton,
+			))),
+			total,
+		),
+		nil, nil, nil,
+		table,
+	), a.mainWindow)
+	compareDialog.Resize(a.mainWindow.Canvas().Size().Subtract(fyne.NewSquareSize(theme.Padding() * 4)))
+	compareDialog.Show()
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const overridesFile = "overrides.json"
+
+var itemTypes = []ItemType{Ore, Alloy, Item}
+
+func (d *jsonGameData) section(itemType ItemType) *[]jsonGameItem {
+	switch itemType {
+	case Alloy:
+		return &d.Alloys
+	case Item:
+		return &d.Items
+	}
+	return &d.Ores
+}
+
+// find returns the type and index of the named item
+func (d *jsonGameData) find(name string) (ItemType, int, bool) {
+	for _, itemType := range itemTypes {
+		if index := slices.IndexFunc(*d.section(itemType), func(item jsonGameItem) bool {
+			return item.Name == name
+		}); index >= 0 {
+			return itemType, index, true
+		}
+	}
+	return Ore, 0, false
+}
+
+// put replaces the item with the same name, wherever it is, or adds it to
+// the end of its section.
+func (d *jsonGameData) put(itemType ItemType, item jsonGameItem) {
+	if existingType, index, found := d.find(item.Name); found {
+		if existingType == itemType {
+			(*d.section(itemType))[index] = item
+			return
+		}
+		d.remove(item.Name)
+	}
+	*d.section(itemType) = append(*d.section(itemType), item)
+}
+
+func (d *jsonGameData) remove(name string) {
+	if itemType, index, found := d.find(name); found {
+		*d.section(itemType) = slices.Delete(*d.section(itemType), index, index+1)
+	}
+}
+
+func (d *jsonGameData) clone() *jsonGameData {
+	clone := *d
+	clone.Ores = slices.Clone(d.Ores)
+	clone.Alloys = slices.Clone(d.Alloys)
+	clone.Items = slices.Clone(d.Items)
+	return &clone
+}
+
+// applyOverrides lays the user's edits over the base game data
+func applyOverrides(base, overrides *jsonGameData) *jsonGameData {
+	merged := base.clone()
+	for _, itemType := range itemTypes {
+		for _, item := range *overrides.section(itemType) {
+			merged.put(itemType, item)
+		}
+	}
+	return merged
+}
+
+func (a *App) loadOverrides() {
+	a.overrides = &jsonGameData{SchemaVersion: currentSchemaVersion}
+	uri, err := storage.Child(a.app.Storage().RootURI(), overridesFile)
+	if err != nil {
+		return
+	}
+	reader, err := storage.Reader(uri)
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return
+	}
+	if overrides, err := parseData(b); err == nil {
+		a.overrides = overrides
+	} else {
+		fmt.Printf("could not load %s: %s\n", overridesFile, err)
+	}
+}
+
+func (a *App) saveOverrides() error {
+	uri, err := storage.Child(a.app.Storage().RootURI(), overridesFile)
+	if err != nil {
+		return err
+	}
+	writer, err := storage.Writer(uri)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+	b, err := encodeData(a.overrides, JSONData)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(b)
+	return err
+}
+
+// setGameData switches to the base data with the overrides applied,
+// keeping the current orders pointing at the updated items.
+func (a *App) setGameData() {
+	merged := applyOverrides(a.source, a.overrides)
+	a.data = getGameData(merged)
+	setItemNames(merged)
+	a.itemList = getItemList(a.data)
+	a.icons.SetData(a.data)
+	if a.orderContainer == nil {
+		return
+	}
+	for _, o := range a.orderContainer.Objects {
+		order := o.(*Order)
+		order.SetOptions(a.itemList)
+		if item, found := a.data[order.orderItem.Name]; found {
+			order.SetItem(item)
+		}
+	}
+	a.inputsChanged()
+}
+
+// getNewDataErrors validates edited game data, ignoring any problems that
+// were already there before the edit.
+func getNewDataErrors(before, after *jsonGameData) error {
+	_, existing := buildGameData(before)
+	_, errs := buildGameData(after)
+	errs = slices.DeleteFunc(errs, func(err error) bool {
+		return slices.ContainsFunc(existing, func(e error) bool { return e.Error() == err.Error() })
+	})
+	return errors.Join(errs...)
+}
+
+// parseIngredients reads one "amount name" ingredient per line
+func parseIngredients(text string) []jsonIngredient {
+	ingredients := make([]jsonIngredient, 0)
+	for _, line := range strings.Split(text, "\n") {
+		if name, amount := parseOrderLine(line); name != "" {
+			ingredients = append(ingredients, jsonIngredient{Name: name, Amount: amount})
+		}
+	}
+	return ingredients
+}
+
+func formatIngredients(ingredients []jsonIngredient) string {
+	lines := make([]string, 0)
+	for _, i := range ingredients {
+		lines = append(lines, fmt.Sprintf("%d %s", i.Amount, i.Name))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (a *App) editorHandler() {
+	typeNames := []string{tr(Ore.String()), tr(Alloy.String()), tr(Item.String())}
+	typeSelect := widget.NewSelect(typeNames, nil)
+	name := widget.NewSelectEntry(nil)
+	value := widget.NewEntry()
+	craftTime := widget.NewEntry()
+	craftTime.SetPlaceHolder(tr("Seconds to smelt / craft one"))
+	ingredients := widget.NewMultiLineEntry()
+	ingredients.SetPlaceHolder("1000 Copper\n500 Iron")
+	ingredients.SetMinRowsVisible(4)
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+
+	updateStatus := func() {
+		merged := applyOverrides(a.source, a.overrides)
+		missing := make([]string, 0)
+		for _, ore := range merged.Ores {
+			if ore.Value == 0 {
+				missing = append(missing, ore.Name)
+			}
+		}
+		lines := []string{fmt.Sprintf(tr("%d edits"), len(a.overrides.Ores)+len(a.overrides.Alloys)+len(a.overrides.Items))}
+		if len(missing) > 0 {
+			lines = append(lines, tr("No value yet: ")+strings.Join(missing, ", "))
+		}
+		status.SetText(strings.Join(lines, "\n"))
+	}
+	getNames := func(itemType ItemType) []string {
+		names := make([]string, 0)
+		for _, item := range *applyOverrides(a.source, a.overrides).section(itemType) {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+	typeSelect.OnChanged = func(string) {
+		name.SetOptions(getNames(ItemType(typeSelect.SelectedIndex())))
+	}
+	name.OnChanged = func(input string) {
+		merged := applyOverrides(a.source, a.overrides)
+		itemType, index, found := merged.find(input)
+		if !found {
+			return
+		}
+		item := (*merged.section(itemType))[index]
+		typeSelect.SetSelectedIndex(int(itemType))
+		value.SetText(strconv.Itoa(item.Value))
+		craftTime.SetText("")
+		if item.Time > 0 {
+			craftTime.SetText(strconv.Itoa(item.Time))
+		}
+		ingredients.SetText(formatIngredients(item.Ingredients))
+	}
+	typeSelect.SetSelectedIndex(int(Ore))
+
+	saveButton := widget.NewButtonWithIcon(tr("Save"), theme.DocumentSaveIcon(), func() {
+		item := jsonGameItem{
+			Name:        strings.TrimSpace(name.Text),
+			Ingredients: parseIngredients(ingredients.Text),
+		}
+		merged := applyOverrides(a.source, a.overrides)
+		if itemType, index, found := merged.find(item.Name); found {
+			item.Names = (*merged.section(itemType))[index].Names
+		}
+		var err error
+		if item.Value, err = parseAmount(value.Text); err != nil {
+			dialog.ShowError(err, a.mainWindow)
+			return
+		}
+		if craftTime.Text != "" {
+			if item.Time, err = strconv.Atoi(craftTime.Text); err != nil {
+				dialog.ShowError(errors.New(tr("Time is in whole seconds")), a.mainWindow)
+				return
+			}
+		}
+		overrides := a.overrides.clone()
+		overrides.put(ItemType(typeSelect.SelectedIndex()), item)
+		if err := getNewDataErrors(applyOverrides(a.source, a.overrides), applyOverrides(a.source, overrides)); err != nil {
+			dialog.ShowError(err, a.mainWindow)
+			return
+		}
+		a.overrides = overrides
+		if err := a.saveOverrides(); err != nil {
+			dialog.ShowError(err, a.mainWindow)
+		}
+		a.setGameData()
+		name.SetOptions(getNames(ItemType(typeSelect.SelectedIndex())))
+		updateStatus()
+	})
+	revertButton := widget.NewButtonWithIcon(tr("Revert"), theme.ContentUndoIcon(), func() {
+		overrides := a.overrides.clone()
+		overrides.remove(strings.TrimSpace(name.Text))
+		if err := getNewDataErrors(applyOverrides(a.source, a.overrides), applyOverrides(a.source, overrides)); err != nil {
+			dialog.ShowError(err, a.mainWindow)
+			return
+		}
+		a.overrides = overrides
+		if err := a.saveOverrides(); err != nil {
+			dialog.ShowError(err, a.mainWindow)
+		}
+		a.setGameData()
+		name.OnChanged(name.Text)
+		updateStatus()
+	})
+	copyButton := widget.NewButtonWithIcon(tr("Copy patch"), theme.ContentCopyIcon(), func() {
+		patch, _ := json.MarshalIndent(a.overrides, "", "  ")
+		a.app.Clipboard().SetContent(string(patch))
+	})
+	exportButton := widget.NewButtonWithIcon(tr("Export patch"), theme.UploadIcon(), func() {
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			defer writer.Close()
+			format, err := getDataFormat(writer.URI().Name())
+			var b []byte
+			if err == nil {
+				b, err = encodeData(a.overrides, format)
+			}
+			if err == nil {
+				_, err = writer.Write(b)
+			}
+			if err != nil {
+				dialog.ShowError(err, a.mainWindow)
+			}
+		}, a.mainWindow)
+		saveDialog.SetFileName("overrides.json")
+		saveDialog.Show()
+	})
+	updateStatus()
+
+	editorDialog := dialog.NewCustom(tr("Edit game data"), tr("Close"), container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem(tr("Type"), typeSelect),
+			widget.NewFormItem(tr("Name"), name),
+			widget.NewFormItem(tr("Value"), value),
+			widget.NewFormItem(tr("Time"), craftTime),
+			widget.NewFormItem(tr("Ingredients"), ingredients),
+		),
+		container.NewHBox(saveButton, revertButton, layout.NewSpacer(), copyButton, exportButton),
+		status,
+	), a.mainWindow)
+	editorDialog.Resize(editorDialog.MinSize().AddWidthHeight(100, 0))
+	editorDialog.Show()
+}
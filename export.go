@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+type ExportFormat int
+
+const (
+	CSV ExportFormat = iota
+	Markdown
+	JSON
+)
+
+var exportFormatName = map[ExportFormat]string{
+	CSV:      "CSV",
+	Markdown: "Markdown",
+	JSON:     "JSON",
+}
+
+var exportFormatExtension = map[ExportFormat]string{
+	CSV:      "csv",
+	Markdown: "md",
+	JSON:     "json",
+}
+
+func (f ExportFormat) String() string {
+	return exportFormatName[f]
+}
+
+type exportData struct {
+	Results []exportRow  `json:"results"`
+	Orders  []ResultItem `json:"orders"`
+	Bonuses Bonuses      `json:"bonuses"`
+}
+
+type exportRow struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Amount int    `json:"amount"`
+	Value  int    `json:"value"`
+}
+
+func newExportData(results []Ingredient, orders []ResultItem, bonuses Bonuses) exportData {
+	data := exportData{
+		Results: make([]exportRow, 0),
+		Orders:  orders,
+		Bonuses: bonuses,
+	}
+	for _, r := range results {
+		data.Results = append(data.Results, exportRow{
+			Name:   r.Item.Name,
+			Type:   r.Item.Type.String(),
+			Amount: r.Amount,
+			Value:  r.Value,
+		})
+	}
+	return data
+}
+
+func (a *App) getExportData() exportData {
+	return newExportData(a.results, a.getDisplayResults(a.orders), a.Bonuses)
+}
+
+func getBonusRows(b Bonuses) [][]string {
+	return [][]string{
+		{"Craft Eff.", strconv.FormatBool(b.CraftingEfficiency)},
+		{"Smelt Eff.", strconv.FormatBool(b.SmeltingEfficiency)},
+		{"Smelt Value", formatBonus(b.SmeltValBonus)},
+		{"Craft Value", formatBonus(b.CraftValBonus)},
+		{"Underforge", formatBonus(b.UnderforgeBonus)},
+		{"Dorms", formatBonus(b.DormsBonus)},
+	}
+}
+
+func writeExport(w io.Writer, format ExportFormat, data exportData) error {
+	switch format {
+	case CSV:
+		return writeCSV(w, data)
+	case Markdown:
+		return writeMarkdown(w, data)
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	default:
+		return fmt.Errorf("unknown export format: %d", format)
+	}
+}
+
+func writeCSV(w io.Writer, data exportData) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"Item", "Type", "Amount", "Value"})
+	for _, r := range data.Results {
+		writer.Write([]string{r.Name, r.Type, strconv.Itoa(r.Amount), strconv.Itoa(r.Value)})
+	}
+
+	writer.Write([]string{})
+	writer.Write([]string{"Group", "Order", "Ingredient", "Amount", "Value"})
+	for _, o := range data.Orders {
+		writer.Write([]string{o.Group, o.Name, "", strconv.Itoa(o.Amount), strconv.Itoa(o.Value)})
+		for _, i := range o.Ingredients {
+			writer.Write([]string{o.Group, o.Name, i.Name, strconv.Itoa(i.Amount), strconv.Itoa(i.Value)})
+		}
+	}
+
+	writer.Write([]string{})
+	writer.Write([]string{"Bonus", "Value"})
+	for _, b := range getBonusRows(data.Bonuses) {
+		writer.Write(b)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeMarkdown(w io.Writer, data exportData) error {
+	var sb strings.Builder
+	sb.WriteString("## Results\n\n")
+	sb.WriteString("| Item | Type | Amount | Value |\n")
+	sb.WriteString("| --- | --- | ---: | ---: |\n")
+	for _, r := range data.Results {
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n",
+			r.Name, r.Type, formatNumber(r.Amount), formatMoney(r.Value))
+	}
+
+	sb.WriteString("\n## Orders\n\n")
+	sb.WriteString("| Group | Order | Amount | Value | Ingredients |\n")
+	sb.WriteString("| --- | --- | ---: | ---: | --- |\n")
+	total := 0
+	for _, o := range data.Orders {
+		ingredients := make([]string, 0)
+		for _, i := range o.Ingredients {
+			ingredients = append(ingredients, fmt.Sprintf("%s x %s", formatNumber(i.Amount), i.Name))
+		}
+		total += o.Value
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n",
+			o.Group, o.Name, formatNumber(o.Amount), formatMoney(o.Value), strings.Join(ingredients, ", "))
+	}
+	fmt.Fprintf(&sb, "\nTotal: %s\n", formatMoney(total))
+
+	sb.WriteString("\n## Bonuses\n\n")
+	sb.WriteString("| Bonus | Value |\n")
+	sb.WriteString("| --- | ---: |\n")
+	for _, b := range getBonusRows(data.Bonuses) {
+		fmt.Fprintf(&sb, "| %s | %s |\n", b[0], b[1])
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (a *App) exportToFile(format ExportFormat) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, a.mainWindow)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+		if err := writeExport(writer, format, a.getExportData()); err != nil {
+			dialog.ShowError(err, a.mainWindow)
+		}
+	}, a.mainWindow)
+	saveDialog.SetFileName("idle-planet-calc." + exportFormatExtension[format])
+	saveDialog.Show()
+}
+
+func (a *App) exportToClipboard(format ExportFormat) {
+	var sb strings.Builder
+	if err := writeExport(&sb, format, a.getExportData()); err != nil {
+		dialog.ShowError(err, a.mainWindow)
+		return
+	}
+	a.app.Clipboard().SetContent(sb.String())
+}
+
+func (a *App) exportHandler() {
+	if len(a.results) == 0 {
+		dialog.ShowInformation(tr("Export"), tr("Nothing to export, calculate some orders first"), a.mainWindow)
+		return
+	}
+
+	formats := []string{CSV.String(), Markdown.String(), JSON.String()}
+	format := CSV
+	formatSelect := widget.NewSelect(formats, func(input string) {
+		for f, name := range exportFormatName {
+			if name == input {
+				format = f
+			}
+		}
+	})
+	formatSelect.SetSelected(format.String())
+
+	var exportDialog dialog.Dialog
+	fileButton := widget.NewButton(tr("Save to file"), func() {
+		exportDialog.Hide()
+		a.exportToFile(format)
+	})
+	clipboardButton := widget.NewButton(tr("Copy to clipboard"), func() {
+		exportDialog.Hide()
+		a.exportToClipboard(format)
+	})
+
+	exportDialog = dialog.NewCustom(tr("Export"), tr("Close"), container.NewVBox(
+		widget.NewForm(widget.NewFormItem(tr("Format"), formatSelect)),
+		container.NewGridWithColumns(2, fileButton, clipboardButton),
+	), a.mainWindow)
+	exportDialog.Show()
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	matchExact = iota
+	matchPrefix
+	matchWordPrefix
+	matchContains
+	matchSubsequence
+	matchDistance
+)
+
+func normaliseName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+func isSubsequence(query, name string) bool {
+	index := 0
+	for _, r := range name {
+		if index < len(query) && rune(query[index]) == r {
+			index++
+		}
+	}
+	return index == len(query)
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// matchScore ranks how well query matches name, lower is better. Names that
+// don't match at all return false.
+func matchScore(query, name string) (int, bool) {
+	query = normaliseName(query)
+	name = normaliseName(name)
+	switch {
+	case query == "":
+		return 0, false
+	case query == name:
+		return matchExact, true
+	case strings.HasPrefix(name, query):
+		return matchPrefix, true
+	case strings.Contains(" "+name, " "+query):
+		return matchWordPrefix, true
+	case strings.Contains(name, query):
+		return matchContains, true
+	case isSubsequence(strings.ReplaceAll(query, " ", ""), name):
+		return matchSubsequence, true
+	}
+	distance := levenshtein(query, name)
+	if distance <= max(2, len(name)/4) {
+		return matchDistance + distance, true
+	}
+	return 0, false
+}
+
+// rankItems returns the names matching query, best match first.
+func rankItems(query string, names []string) []string {
+	scores := make(map[string]int)
+	matches := make([]string, 0)
+	for _, name := range names {
+		if score, ok := matchScore(query, name); ok {
+			scores[name] = score
+			matches = append(matches, name)
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b string) int {
+		return scores[a] - scores[b]
+	})
+	return matches
+}
+
+// matchItem resolves query to a single name, exact reports whether it was
+// an exact (case insensitive) match.
+func matchItem(query string, names []string) (name string, exact bool, found bool) {
+	matches := rankItems(query, names)
+	if len(matches) == 0 {
+		return "", false, false
+	}
+	score, _ := matchScore(query, matches[0])
+	return matches[0], score == matchExact, true
+}
@@ -0,0 +1,112 @@
+package main
+
+import "time"
+
+const (
+	defaultHistoryDepth = 50
+	historyMergeWindow  = time.Second
+)
+
+type historyEntry struct {
+	key  string
+	time time.Time
+	undo func()
+	redo func()
+}
+
+// History is a capped undo/redo stack. Consecutive entries recorded with the
+// same non-empty key inside historyMergeWindow are merged into one, so typing
+// into a field undoes as a single edit.
+type History struct {
+	undoStack []historyEntry
+	redoStack []historyEntry
+	depth     int
+	replaying bool
+	onChanged func()
+}
+
+func NewHistory(depth int) *History {
+	return &History{
+		undoStack: make([]historyEntry, 0),
+		redoStack: make([]historyEntry, 0),
+		depth:     depth,
+	}
+}
+
+func (h *History) SetOnChanged(onChanged func()) {
+	h.onChanged = onChanged
+}
+
+func (h *History) SetDepth(depth int) {
+	h.depth = depth
+	h.trim()
+	h.changed()
+}
+
+// Record adds an edit to the history, edits made while undoing or redoing
+// are ignored.
+func (h *History) Record(key string, undo, redo func()) {
+	if h.replaying {
+		return
+	}
+	now := time.Now()
+	h.redoStack = h.redoStack[:0]
+	if last := len(h.undoStack) - 1; last >= 0 && key != "" &&
+		h.undoStack[last].key == key && now.Sub(h.undoStack[last].time) < historyMergeWindow {
+		h.undoStack[last].redo = redo
+		h.undoStack[last].time = now
+	} else {
+		h.undoStack = append(h.undoStack, historyEntry{key, now, undo, redo})
+		h.trim()
+	}
+	h.changed()
+}
+
+func (h *History) CanUndo() bool {
+	return len(h.undoStack) > 0
+}
+
+func (h *History) CanRedo() bool {
+	return len(h.redoStack) > 0
+}
+
+func (h *History) Undo() {
+	if !h.CanUndo() {
+		return
+	}
+	entry := h.undoStack[len(h.undoStack)-1]
+	h.undoStack = h.undoStack[:len(h.undoStack)-1]
+	h.replay(entry.undo)
+	h.redoStack = append(h.redoStack, entry)
+	h.changed()
+}
+
+func (h *History) Redo() {
+	if !h.CanRedo() {
+		return
+	}
+	entry := h.redoStack[len(h.redoStack)-1]
+	h.redoStack = h.redoStack[:len(h.redoStack)-1]
+	h.replay(entry.redo)
+	entry.key = ""
+	h.undoStack = append(h.undoStack, entry)
+	h.changed()
+}
+
+func (h *History) replay(action func()) {
+	h.replaying = true
+	defer func() { h.replaying = false }()
+	action()
+}
+
+func (h *History) trim() {
+	if h.depth > 0 && len(h.undoStack) > h.depth {
+		h.undoStack = h.undoStack[len(h.undoStack)-h.depth:]
+	}
+}
+
+func (h *History) changed() {
+	if h.onChanged != nil {
+		h.onChanged()
+	}
+}
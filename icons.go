@@ -0,0 +1,111 @@
+package main
+
+import (
+	"embed"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+//go:embed icons
+var iconFiles embed.FS
+
+var iconExtensions = []string{".svg", ".png", ".jpg"}
+
+var placeholderIcons = map[ItemType]string{
+	Ore:   "ore.svg",
+	Alloy: "alloy.svg",
+	Item:  "item.svg",
+}
+
+// IconSet finds the icon for an item by name, first in the override
+// directory then in the embedded icons, falling back to a placeholder in the
+// item type's colour.
+type IconSet struct {
+	overrideDir string
+	data        map[string]GameItem
+	cache       map[string]fyne.Resource
+}
+
+func NewIconSet(overrideDir string, data map[string]GameItem) *IconSet {
+	return &IconSet{
+		overrideDir: overrideDir,
+		data:        data,
+		cache:       make(map[string]fyne.Resource),
+	}
+}
+
+// iconName turns "Copper Bar" into "copper-bar"
+func iconName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), "-")
+}
+
+// SetOverrideDir changes where users can drop in their own icons, named
+// like "copper-bar.png".
+func (i *IconSet) SetOverrideDir(dir string) {
+	i.overrideDir = dir
+	i.cache = make(map[string]fyne.Resource)
+}
+
+func (i *IconSet) SetData(data map[string]GameItem) {
+	i.data = data
+	i.cache = make(map[string]fyne.Resource)
+}
+
+func (i *IconSet) Icon(name string) fyne.Resource {
+	if i == nil || name == "" {
+		return nil
+	}
+	if icon, found := i.cache[name]; found {
+		return icon
+	}
+	icon := i.load(iconName(name))
+	if icon == nil {
+		itemType := i.data[name].Type
+		placeholder, _ := iconFiles.ReadFile(path.Join("icons", placeholderIcons[itemType]))
+		icon = theme.NewColoredResource(fyne.NewStaticResource(placeholderIcons[itemType], placeholder), itemTypeColorName[itemType])
+	}
+	i.cache[name] = icon
+	return icon
+}
+
+// ColorName is the colour of the item's type, empty for unknown items
+func (i *IconSet) ColorName(name string) fyne.ThemeColorName {
+	if i == nil {
+		return ""
+	}
+	item, found := i.data[name]
+	if !found {
+		return ""
+	}
+	return itemTypeColorName[item.Type]
+}
+
+func (i *IconSet) load(name string) fyne.Resource {
+	for _, ext := range iconExtensions {
+		if i.overrideDir == "" {
+			break
+		}
+		if b, err := os.ReadFile(filepath.Join(i.overrideDir, name+ext)); err == nil {
+			return fyne.NewStaticResource(name+ext, b)
+		}
+	}
+	for _, ext := range iconExtensions {
+		if b, err := iconFiles.ReadFile(path.Join("icons", name+ext)); err == nil {
+			return fyne.NewStaticResource(name+ext, b)
+		}
+	}
+	return nil
+}
+
+func (a *App) getIconDir() string {
+	root := a.app.Storage().RootURI()
+	if root.Scheme() != "file" {
+		return ""
+	}
+	return filepath.Join(root.Path(), "icons")
+}
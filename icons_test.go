@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIcons(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "copper-bar.png"), []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data := getGameData(loadData())
+	data["Unobtainium"] = GameItem{Name: "Unobtainium", Type: Ore}
+	icons := NewIconSet(dir, data)
+	for name, want := range map[string]string{
+		"Copper Bar":  "copper-bar.png",
+		"Iron Bar":    "iron-bar.svg",
+		"Copper":      "copper.svg",
+		"Robot":       "robot.svg",
+		"Unobtainium": "ore.svg",
+	} {
+		if icon := icons.Icon(name); !strings.HasSuffix(icon.Name(), want) {
+			t.Errorf("%s has icon %s, want %s", name, icon.Name(), want)
+		}
+	}
+
+	icons.SetOverrideDir("")
+	for name := range getGameData(loadData()) {
+		if icon := icons.Icon(name); icon.Name() != iconName(name)+".svg" {
+			t.Errorf("no icon for %s, got %s", name, icon.Name())
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	shareCodePrefix = "ipc1:"
+	// maxShareCodeText is far more orders than anyone types, it stops a
+	// small code unpacking into gigabytes.
+	maxShareCodeText = 1 << 20
+)
+
+type importResult struct {
+	Orders  []Ingredient
+	Matched map[string]string
+	Unknown []string
+}
+
+func (r importResult) Report() string {
+	lines := make([]string, 0)
+	for _, input := range slices.Sorted(maps.Keys(r.Matched)) {
+		lines = append(lines, fmt.Sprintf(tr("\"%s\" matched %s"), input, r.Matched[input]))
+	}
+	for _, input := range r.Unknown {
+		lines = append(lines, fmt.Sprintf(tr("\"%s\" not found"), input))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatOrders writes one order per line, with a "# group" line before
+// each group of orders.
+func formatOrders(orders []Ingredient) string {
+	lines := make([]string, 0)
+	group := ""
+	for _, o := range orders {
+		if o.Item.Name == "" {
+			continue
+		}
+		if o.Group != group {
+			group = o.Group
+			lines = append(lines, strings.TrimSpace("# "+group))
+		}
+		lines = append(lines, fmt.Sprintf("%d %s", o.Amount, o.Item.Name))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func getShareCode(orders []Ingredient) string {
+	var buf bytes.Buffer
+	writer, _ := flate.NewWriter(&buf, flate.BestCompression)
+	writer.Write([]byte(formatOrders(orders)))
+	writer.Close()
+	return shareCodePrefix + base64.RawURLEncoding.EncodeToString(buf.Bytes())
+}
+
+func decodeShareCode(code string) (string, error) {
+	compressed, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(code, shareCodePrefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid share code: %w", err)
+	}
+	reader := io.LimitReader(flate.NewReader(bytes.NewReader(compressed)), maxShareCodeText+1)
+	text, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("invalid share code: %w", err)
+	}
+	if len(text) > maxShareCodeText {
+		return "", errors.New("invalid share code: too many orders")
+	}
+	return string(text), nil
+}
+
+// parseOrderLine splits a line such as "2 Fusion Reactor", "2x Robot" or
+// "Robot x 1.5K" into a name and an amount, defaulting the amount to 1.
+// Amounts take the same notation as the amount fields.
+func parseOrderLine(line string) (name string, amount int) {
+	fields := strings.Fields(line)
+	amount = 1
+	if len(fields) == 0 {
+		return
+	}
+	orderAmount := func(field string) (int, bool) {
+		val, err := parseAmount(strings.TrimSuffix(strings.TrimPrefix(strings.ToLower(field), "x"), "x"))
+		if err != nil || val <= 0 {
+			return 0, false
+		}
+		return val, true
+	}
+	if len(fields) > 1 {
+		if val, ok := orderAmount(fields[0]); ok {
+			return strings.Join(fields[1:], " "), val
+		}
+		if val, ok := orderAmount(fields[len(fields)-1]); ok {
+			fields = fields[:len(fields)-1]
+			if len(fields) > 1 && strings.EqualFold(fields[len(fields)-1], "x") {
+				fields = fields[:len(fields)-1]
+			}
+			return strings.Join(fields, " "), val
+		}
+	}
+	return strings.Join(fields, " "), amount
+}
+
+// parseOrders reads a plain list of orders, one per line with optional
+// "# group" headings, or a share code and resolves each name against the
+// orderable items.
+func parseOrders(text string, data map[string]GameItem, names []string) (result importResult, err error) {
+	result = importResult{
+		Orders:  make([]Ingredient, 0),
+		Matched: make(map[string]string),
+		Unknown: make([]string, 0),
+	}
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, shareCodePrefix) {
+		if text, err = decodeShareCode(text); err != nil {
+			return
+		}
+	}
+
+	group := ""
+	for _, line := range strings.FieldsFunc(text, func(r rune) bool {
+		return r == '\n' || r == ';'
+	}) {
+		if heading, found := strings.CutPrefix(strings.TrimSpace(line), "#"); found {
+			group = strings.TrimSpace(heading)
+			continue
+		}
+		name, amount := parseOrderLine(line)
+		if name == "" {
+			continue
+		}
+		match, exact, found := matchItem(name, names)
+		if !found {
+			result.Unknown = append(result.Unknown, name)
+			continue
+		}
+		if !exact {
+			result.Matched[name] = match
+		}
+		result.Orders = append(result.Orders, Ingredient{
+			Item:   data[match],
+			Amount: amount,
+			Group:  group,
+		})
+	}
+	return
+}
+
+func (a *App) importOrders(text string, replace bool) {
+	result, err := parseOrders(text, a.data, a.itemList)
+	if err != nil {
+		dialog.ShowError(err, a.mainWindow)
+		return
+	}
+	a.addOrders(result.Orders, replace)
+	if report := result.Report(); report != "" {
+		dialog.ShowInformation(tr("Import"), report, a.mainWindow)
+	}
+}
+
+// addOrders adds a batch of orders as a single edit, optionally replacing
+// the current ones.
+func (a *App) addOrders(orders []Ingredient, replace bool) {
+	before := a.getOrderRows()
+	if replace {
+		a.orderContainer.RemoveAll()
+		a.inputsChanged()
+	}
+	for _, o := range orders {
+		a.addOrder(o)
+	}
+	a.recordOrderRows(before)
+}
+
+func (a *App) savePlans() {
+	plans, _ := json.Marshal(a.plans)
+	a.app.Preferences().SetString("plans", string(plans))
+}
+
+func (a *App) loadPlans() {
+	a.plans = make(map[string]string)
+	json.Unmarshal([]byte(a.app.Preferences().String("plans")), &a.plans)
+}
+
+func (a *App) plansHandler() {
+	var plansDialog dialog.Dialog
+
+	input := widget.NewMultiLineEntry()
+	input.SetPlaceHolder(tr("2 Fusion Reactor\n10 Robot\n\nor paste a share code"))
+	input.SetMinRowsVisible(4)
+	replace := widget.NewCheck(tr("Replace current orders"), nil)
+	importButton := widget.NewButtonWithIcon(tr("Import"), theme.ContentPasteIcon(), func() {
+		plansDialog.Hide()
+		a.importOrders(input.Text, replace.Checked)
+	})
+	shareButton := widget.NewButtonWithIcon(tr("Copy share code"), theme.ContentCopyIcon(), func() {
+		a.app.Clipboard().SetContent(getShareCode(a.getOrders()))
+	})
+
+	planSelect := widget.NewSelect(slices.Sorted(maps.Keys(a.plans)), nil)
+	planSelect.PlaceHolder = tr("Saved plans")
+	loadButton := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+		if plan, found := a.plans[planSelect.Selected]; found {
+			plansDialog.Hide()
+			a.importOrders(plan, true)
+		}
+	})
+	deleteButton := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		delete(a.plans, planSelect.Selected)
+		a.savePlans()
+		planSelect.ClearSelected()
+		planSelect.SetOptions(slices.Sorted(maps.Keys(a.plans)))
+	})
+	planName := widget.NewEntry()
+	planName.SetPlaceHolder(tr("Plan name"))
+	saveButton := widget.NewButtonWithIcon("", theme.DocumentSaveIcon(), func() {
+		if planName.Text == "" {
+			return
+		}
+		a.plans[planName.Text] = formatOrders(a.getOrders())
+		a.savePlans()
+		planSelect.SetOptions(slices.Sorted(maps.Keys(a.plans)))
+		planSelect.SetSelected(planName.Text)
+		planName.SetText("")
+	})
+
+	plansDialog = dialog.NewCustom(tr("Plans"), tr("Close"), container.NewVBox(
+		input,
+		replace,
+		container.NewHBox(importButton, layout.NewSpacer(), shareButton),
+		widget.NewSeparator(),
+		container.NewBorder(nil, nil, nil, container.NewHBox(loadButton, deleteButton), planSelect),
+		container.NewBorder(nil, nil, nil, saveButton, planName),
+	), a.mainWindow)
+	plansDialog.Resize(plansDialog.MinSize().AddWidthHeight(60, 0))
+	plansDialog.Show()
+}
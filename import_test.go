@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"testing"
+)
+
+func TestShareCode(t *testing.T) {
+	data := getGameData(loadData())
+	orders := []Ingredient{
+		{Item: data["Copper Bar"], Amount: 3},
+		{Item: data["Iron Bar"], Amount: 2, Group: "Bars"},
+	}
+	text, err := decodeShareCode(getShareCode(orders))
+	if err != nil || text != formatOrders(orders) {
+		t.Errorf("decoded %q, %v", text, err)
+	}
+
+	var buf bytes.Buffer
+	writer, _ := flate.NewWriter(&buf, flate.BestCompression)
+	writer.Write(bytes.Repeat([]byte("1 Copper Bar\n"), maxShareCodeText))
+	writer.Close()
+	if _, err := decodeShareCode(shareCodePrefix + base64.RawURLEncoding.EncodeToString(buf.Bytes())); err == nil {
+		t.Error("no error for a share code over the limit")
+	}
+}
+
+func TestParseOrderLine(t *testing.T) {
+	for line, want := range map[string]struct {
+		name   string
+		amount int
+	}{
+		"2 Fusion Reactor":  {"Fusion Reactor", 2},
+		"2x Robot":          {"Robot", 2},
+		"Robot x 10":        {"Robot", 10},
+		"Copper Bar 1.5K":   {"Copper Bar", 1500},
+		"2e3 Iron Bar":      {"Iron Bar", 2000},
+		"Battery x1.5aa":    {"Battery", 1.5e15},
+		"Advanced Computer": {"Advanced Computer", 1},
+		"0 Robot":           {"0 Robot", 1},
+	} {
+		if name, amount := parseOrderLine(line); name != want.name || amount != want.amount {
+			t.Errorf("parseOrderLine(%q) = %q, %d, want %q, %d", line, name, amount, want.name, want.amount)
+		}
+	}
+}
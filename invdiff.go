@@ -0,0 +1,287 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const currentOrdersPlan = "Current orders"
+
+var dataChangePrefix = map[string]string{
+	"added":   "+",
+	"removed": "-",
+	"changed": "~",
+}
+
+type dataChange struct {
+	Kind    string // added, removed or changed
+	Type    ItemType
+	Name    string
+	Details []string
+}
+
+func (c dataChange) String() string {
+	line := fmt.Sprintf("%s %s %s", dataChangePrefix[c.Kind], c.Type, c.Name)
+	if len(c.Details) > 0 {
+		line += ": " + strings.Join(c.Details, ", ")
+	}
+	return line
+}
+
+// diffData lists the ores, alloys and items added, removed or changed
+// between two sets of game data, in the order they appear in the files.
+func diffData(before, after *jsonGameData) []dataChange {
+	changes := make([]dataChange, 0)
+	sections := []struct {
+		itemType      ItemType
+		before, after []jsonGameItem
+	}{
+		{Ore, before.Ores, after.Ores},
+		{Alloy, before.Alloys, after.Alloys},
+		{Item, before.Items, after.Items},
+	}
+	for _, section := range sections {
+		oldItems := make(map[string]jsonGameItem)
+		for _, item := range section.before {
+			oldItems[item.Name] = item
+		}
+		newItems := make(map[string]bool)
+		for _, item := range section.after {
+			newItems[item.Name] = true
+			previous, found := oldItems[item.Name]
+			if !found {
+				changes = append(changes, dataChange{"added", section.itemType, item.Name,
+					[]string{fmt.Sprintf("value %s", formatNumber(item.Value))}})
+				continue
+			}
+			if details := diffItem(previous, item); len(details) > 0 {
+				changes = append(changes, dataChange{"changed", section.itemType, item.Name, details})
+			}
+		}
+		for _, item := range section.before {
+			if !newItems[item.Name] {
+				changes = append(changes, dataChange{"removed", section.itemType, item.Name, nil})
+			}
+		}
+	}
+	return changes
+}
+
+func diffItem(before, after jsonGameItem) []string {
+	details := make([]string, 0)
+	if before.Value != after.Value {
+		details = append(details, fmt.Sprintf("value %s → %s",
+			formatNumber(before.Value), formatNumber(after.Value)))
+	}
+	if before.Time != after.Time {
+		details = append(details, fmt.Sprintf("time %s → %s",
+			time.Duration(before.Time)*time.Second, time.Duration(after.Time)*time.Second))
+	}
+
+	oldAmounts := make(map[string]int)
+	for _, i := range before.Ingredients {
+		oldAmounts[i.Name] += i.Amount
+	}
+	newAmounts := make(map[string]int)
+	for _, i := range after.Ingredients {
+		newAmounts[i.Name] += i.Amount
+	}
+	for _, i := range after.Ingredients {
+		previous, found := oldAmounts[i.Name]
+		if !found {
+			details = append(details, fmt.Sprintf("+%d %s", i.Amount, i.Name))
+		} else if previous != newAmounts[i.Name] {
+			details = append(details, fmt.Sprintf("%s %d → %d", i.Name, previous, newAmounts[i.Name]))
+		}
+	}
+	for _, i := range before.Ingredients {
+		if _, found := newAmounts[i.Name]; !found {
+			details = append(details, fmt.Sprintf("-%s", i.Name))
+		}
+	}
+	return slices.Compact(details)
+}
+
+// diffPlan works a plan out against both sets of game data, notes lists
+// any order that only matched loosely or not at all.
+func (b Bonuses) diffPlan(plan string, before, after *jsonGameData) (result comparison, notes []string, err error) {
+	notes = make([]string, 0)
+	getOrders := func(label string, data *jsonGameData) ([]Ingredient, error) {
+		gameData := getGameData(data)
+		parsed, err := parseOrders(plan, gameData, getItemList(gameData))
+		if report := parsed.Report(); report != "" {
+			for _, line := range strings.Split(report, "\n") {
+				notes = append(notes, fmt.Sprintf("%s: %s", label, line))
+			}
+		}
+		return parsed.Orders, err
+	}
+	oldOrders, err := getOrders("old", before)
+	if err != nil {
+		return
+	}
+	newOrders, err := getOrders("new", after)
+	if err != nil {
+		return
+	}
+	return compareOrders(b, oldOrders, b, newOrders), notes, nil
+}
+
+func formatDataChanges(changes []dataChange) string {
+	if len(changes) == 0 {
+		return "No changes"
+	}
+	lines := make([]string, 0)
+	for _, c := range changes {
+		lines = append(lines, c.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatComparison(c comparison) string {
+	lines := []string{fmt.Sprintf("%-22s %14s %14s %14s", "Item", "Old", "New", "Change")}
+	for _, row := range c.Rows {
+		if row.CurrentAmount == row.ProjectedAmount {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%-22s %14s %14s %14s", row.Name,
+			formatNumber(row.CurrentAmount), formatNumber(row.ProjectedAmount),
+			formatDelta(row.ProjectedAmount-row.CurrentAmount)))
+	}
+	if len(lines) == 1 {
+		lines = []string{"Bill of materials unchanged"}
+	}
+	lines = append(lines, fmt.Sprintf("Order value: $%s → $%s (%s)",
+		formatNumber(c.CurrentTotal), formatNumber(c.ProjectedTotal),
+		formatDelta(c.ProjectedTotal-c.CurrentTotal)))
+	return strings.Join(lines, "\n")
+}
+
+func (b Bonuses) writeDataDiff(w io.Writer, before, after *jsonGameData, plan string) error {
+	fmt.Fprintln(w, formatDataChanges(diffData(before, after)))
+	if plan == "" {
+		return nil
+	}
+	result, notes, err := b.diffPlan(plan, before, after)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nPlan\n%s\n", formatComparison(result))
+	for _, note := range notes {
+		fmt.Fprintln(w, note)
+	}
+	return nil
+}
+
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	planFlag := flags.String("plan", "", "saved plan name or orders such as \"2 Robot; 1 Fusion Reactor\" to show the impact on")
+	prefsPath := flags.String("prefs", getPreferencesPath(), "preferences file to read saved plans and bonuses from")
+	files := make([]string, 0)
+	for rest := args; ; {
+		flags.Parse(rest)
+		if flags.NArg() == 0 {
+			break
+		}
+		files = append(files, flags.Arg(0))
+		rest = flags.Args()[1:]
+	}
+	if len(files) != 2 {
+		return errors.New("usage: diff [--plan <plan>] <old.json> <new.json>")
+	}
+
+	before, err := readDataFile(files[0])
+	if err != nil {
+		return err
+	}
+	after, err := readDataFile(files[1])
+	if err != nil {
+		return err
+	}
+	prefs, err := openFilePreferences(*prefsPath)
+	if err != nil {
+		return fmt.Errorf("could not read preferences: %w", err)
+	}
+	plan := *planFlag
+	plans := make(map[string]string)
+	json.Unmarshal([]byte(prefs.StringWithFallback("plans", "")), &plans)
+	if saved, found := plans[plan]; found {
+		plan = saved
+	}
+	return loadBonuses(prefs).writeDataDiff(os.Stdout, before, after, plan)
+}
+
+func (a *App) diffHandler() {
+	var newData *jsonGameData
+
+	report := widget.NewLabel(tr("Pick the new game data to compare with"))
+	report.TextStyle.Monospace = true
+	planOptions := append([]string{tr(currentOrdersPlan)}, slices.Sorted(maps.Keys(a.plans))...)
+	planSelect := widget.NewSelect(planOptions, nil)
+	planSelect.SetSelectedIndex(0)
+
+	update := func() {
+		if newData == nil {
+			return
+		}
+		plan := a.plans[planSelect.Selected]
+		if planSelect.SelectedIndex() == 0 {
+			plan = formatOrders(a.getOrders())
+		}
+		var out strings.Builder
+		if err := a.writeDataDiff(&out, a.source, newData, plan); err != nil {
+			report.SetText(err.Error())
+			return
+		}
+		report.SetText(out.String())
+	}
+	planSelect.OnChanged = func(string) { update() }
+
+	openButton := widget.NewButtonWithIcon(tr("Open new data..."), theme.FolderOpenIcon(), func() {
+		openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			defer reader.Close()
+			format, err := getDataFormat(reader.URI().Name())
+			var b []byte
+			if err == nil {
+				b, err = io.ReadAll(reader)
+			}
+			if err == nil {
+				newData, err = decodeData(b, format)
+			}
+			if err != nil {
+				newData = nil
+				dialog.ShowError(err, a.mainWindow)
+				return
+			}
+			update()
+		}, a.mainWindow)
+		openDialog.SetFilter(storage.NewExtensionFileFilter(slices.Collect(maps.Keys(dataFormatExtensions))))
+		openDialog.Show()
+	})
+
+	diffDialog := dialog.NewCustom(tr("Inventory diff"), tr("Close"), container.NewBorder(
+		container.NewVBox(openButton, widget.NewForm(widget.NewFormItem(tr("Plan"), planSelect))),
+		nil, nil, nil,
+		container.NewScroll(report),
+	), a.mainWindow)
+	diffDialog.Resize(a.mainWindow.Canvas().Size().Subtract(fyne.NewSquareSize(theme.Padding() * 4)))
+	diffDialog.Show()
+}
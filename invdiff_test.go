@@ -0,0 +1,23 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiffPlan(t *testing.T) {
+	result, notes, err := NewBonuses().diffPlan("3 Copper Bar", loadData(), changedCopperBar())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) > 0 {
+		t.Errorf("unexpected notes %v", notes)
+	}
+	index := slices.IndexFunc(result.Rows, func(row comparisonRow) bool { return row.Name == "Copper" })
+	if index < 0 {
+		t.Fatalf("no Copper in %v", result.Rows)
+	}
+	if row := result.Rows[index]; row.CurrentAmount != 3000 || row.ProjectedAmount != 1500 {
+		t.Errorf("Copper %d → %d, want 3000 → 1500", row.CurrentAmount, row.ProjectedAmount)
+	}
+}
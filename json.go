@@ -1,32 +1,206 @@
-package main
-
-import (
-	_ "embed"
-	"encoding/json"
-)
-
-//go:embed inventory.json
-var inventoryBytes []byte
-
-type jsonGameData struct {
-	Ores   []jsonGameItem `json:"ores"`
-	Alloys []jsonGameItem `json:"alloys"`
-	Items  []jsonGameItem `json:"items"`
-}
-
-type jsonGameItem struct {
-	Name        string           `json:"name"`
-	Value       int              `json:"value"`
-	Ingredients []jsonIngredient `json:"ingredients"`
-}
-
-type jsonIngredient struct {
-	Name   string `json:"name"`
-	Amount int    `json:"amount"`
-}
-
-func loadData() *jsonGameData {
-	var data jsonGameData
-	json.Unmarshal(inventoryBytes, &data)
-	return &data
-}
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed inventory.json
+var inventoryBytes []byte
+
+// currentSchemaVersion is the layout of the game data this build reads,
+// older files are migrated up to it as they're loaded.
+const currentSchemaVersion = 2
+
+type DataFormat int
+
+const (
+	JSONData DataFormat = iota
+	YAMLData
+	TOMLData
+)
+
+var dataFormatExtensions = map[string]DataFormat{
+	".json": JSONData,
+	".yaml": YAMLData,
+	".yml":  YAMLData,
+	".toml": TOMLData,
+}
+
+type jsonGameData struct {
+	SchemaVersion int            `json:"schemaVersion" yaml:"schemaVersion" toml:"schemaVersion"`
+	GameVersion   string         `json:"gameVersion,omitempty" yaml:"gameVersion,omitempty" toml:"gameVersion,omitempty"`
+	Updated       string         `json:"updated,omitempty" yaml:"updated,omitempty" toml:"updated,omitempty"`
+	Ores          []jsonGameItem `json:"ores" yaml:"ores" toml:"ores"`
+	Alloys        []jsonGameItem `json:"alloys" yaml:"alloys" toml:"alloys"`
+	Items         []jsonGameItem `json:"items" yaml:"items" toml:"items"`
+}
+
+type jsonGameItem struct {
+	Name        string            `json:"name" yaml:"name" toml:"name"`
+	Value       int               `json:"value" yaml:"value" toml:"value"`
+	Time        int               `json:"time,omitempty" yaml:"time,omitempty" toml:"time,omitzero"`
+	Names       map[string]string `json:"names,omitempty" yaml:"names,omitempty" toml:"names,omitempty"` // by language code
+	Ingredients []jsonIngredient  `json:"ingredients,omitempty" yaml:"ingredients,omitempty" toml:"ingredients,omitempty"`
+}
+
+type jsonIngredient struct {
+	Name   string `json:"name" yaml:"name" toml:"name"`
+	Amount int    `json:"amount" yaml:"amount" toml:"amount"`
+}
+
+func getDataFormat(path string) (DataFormat, error) {
+	format, found := dataFormatExtensions[strings.ToLower(filepath.Ext(path))]
+	if !found {
+		return JSONData, fmt.Errorf("unknown game data format: %s, use .json, .yaml or .toml", path)
+	}
+	return format, nil
+}
+
+func loadData() *jsonGameData {
+	data, _ := parseData(inventoryBytes)
+	return data
+}
+
+func parseData(b []byte) (*jsonGameData, error) {
+	var data jsonGameData
+	var raw map[string]any
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return &data, fmt.Errorf("invalid game data: %w", err)
+	}
+	if raw == nil {
+		return &data, errors.New("invalid game data: no ores, alloys or items")
+	}
+	if err := migrateData(raw); err != nil {
+		return &data, err
+	}
+	migrated, _ := json.Marshal(raw)
+	if err := json.Unmarshal(migrated, &data); err != nil {
+		return &data, fmt.Errorf("invalid game data: %w", err)
+	}
+	return &data, nil
+}
+
+// dataMigrations upgrade game data from the keyed schema version to the next
+var dataMigrations = map[int]func(raw map[string]any){
+	1: migrateDataV1,
+}
+
+// migrateData upgrades raw game data in place, files from before the schema
+// was versioned count as version 1.
+func migrateData(raw map[string]any) error {
+	version := 1
+	if val, ok := raw["schemaVersion"].(float64); ok {
+		version = int(val)
+	}
+	if version > currentSchemaVersion {
+		return fmt.Errorf("game data schema %d is newer than this app supports (%d), try updating", version, currentSchemaVersion)
+	}
+	for ; version < currentSchemaVersion; version++ {
+		migration, found := dataMigrations[version]
+		if !found {
+			return fmt.Errorf("no migration from game data schema %d", version)
+		}
+		migration(raw)
+		raw["schemaVersion"] = version + 1
+	}
+	return nil
+}
+
+// migrateDataV1 makes sure every alloy and item has an ingredient list,
+// version 2 also adds the optional version, date and time fields.
+func migrateDataV1(raw map[string]any) {
+	for _, section := range []string{"alloys", "items"} {
+		items, _ := raw[section].([]any)
+		for _, item := range items {
+			if fields, ok := item.(map[string]any); ok && fields["ingredients"] == nil {
+				fields["ingredients"] = []any{}
+			}
+		}
+	}
+}
+
+// decodeData reads game data in any of the formats, YAML and TOML are
+// turned into JSON first so they get the same migrations.
+func decodeData(b []byte, format DataFormat) (*jsonGameData, error) {
+	raw := make(map[string]any)
+	switch format {
+	case YAMLData:
+		if err := yaml.Unmarshal(b, &raw); err != nil {
+			return &jsonGameData{}, fmt.Errorf("invalid game data: %w", err)
+		}
+	case TOMLData:
+		if err := toml.Unmarshal(b, &raw); err != nil {
+			return &jsonGameData{}, fmt.Errorf("invalid game data: %w", err)
+		}
+	default:
+		return parseData(b)
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return &jsonGameData{}, fmt.Errorf("invalid game data: %w", err)
+	}
+	return parseData(b)
+}
+
+func encodeData(data *jsonGameData, format DataFormat) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case YAMLData:
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		err = encoder.Encode(data)
+	case TOMLData:
+		err = toml.NewEncoder(&buf).Encode(data)
+	default:
+		encoder := json.NewEncoder(&buf)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(data)
+	}
+	return buf.Bytes(), err
+}
+
+func readDataFile(path string) (*jsonGameData, error) {
+	format, err := getDataFormat(path)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeData(b, format)
+}
+
+func writeDataFile(path string, data *jsonGameData) error {
+	format, err := getDataFormat(path)
+	if err != nil {
+		return err
+	}
+	b, err := encodeData(data, format)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+func runConvert(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: convert <from> <to>, formats picked by extension (.json, .yaml, .toml)")
+	}
+	data, err := readDataFile(args[0])
+	if err != nil {
+		return err
+	}
+	return writeDataFile(args[1], data)
+}
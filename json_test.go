@@ -0,0 +1,104 @@
+package main
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// checkGameData builds loaded game data and calculates one of everything,
+// which must never panic, and data without errors must add up.
+func checkGameData(t *testing.T, data *jsonGameData) {
+	items, errs := buildGameData(data)
+	bonuses := NewBonuses()
+	for name, item := range items {
+		bonuses.calculate([]Ingredient{{Item: item, Amount: 1}})
+		if len(errs) > 0 {
+			continue
+		}
+		if name == "" || item.Value < 0 || item.Time < 0 {
+			t.Errorf("no error for %q with value %d and time %d", name, item.Value, item.Time)
+		}
+		for _, i := range item.Ingredients {
+			if i.Amount <= 0 {
+				t.Errorf("no error for %s needing %d %s", name, i.Amount, i.Item.Name)
+			}
+		}
+	}
+	if len(errs) == 0 {
+		count := len(data.Ores) + len(data.Alloys) + len(data.Items)
+		if len(items) != count {
+			t.Errorf("no error for %d items loading as %d", count, len(items))
+		}
+	}
+}
+
+// TestDataMetadata checks the game release and date the built in data was
+// checked against, both are left out until they're known.
+func TestDataMetadata(t *testing.T) {
+	data := loadData()
+	if data.GameVersion != "" && !regexp.MustCompile(`^[0-9]+(\.[0-9]+)+$`).MatchString(data.GameVersion) {
+		t.Errorf("game version %q isn't a release number like 1.2.3", data.GameVersion)
+	}
+	if data.Updated != "" {
+		if _, err := time.Parse(time.DateOnly, data.Updated); err != nil {
+			t.Errorf("updated %q isn't a YYYY-MM-DD date: %s", data.Updated, err)
+		}
+	}
+}
+
+// TestConvertRoundTrip converts the built in data to YAML and TOML and back,
+// the result has to write out the same as inventory.json.
+func TestConvertRoundTrip(t *testing.T) {
+	want := strings.ReplaceAll(string(inventoryBytes), "\r\n", "\n")
+	for _, format := range []DataFormat{JSONData, YAMLData, TOMLData} {
+		encoded, err := encodeData(loadData(), format)
+		if err != nil {
+			t.Fatalf("format %d: %s", format, err)
+		}
+		data, err := decodeData(encoded, format)
+		if err != nil {
+			t.Fatalf("format %d: %s", format, err)
+		}
+		if !reflect.DeepEqual(data, loadData()) {
+			t.Errorf("format %d: data changed on the way through", format)
+		}
+		if got, _ := encodeData(data, JSONData); string(got) != want {
+			t.Errorf("format %d: written out differently to inventory.json", format)
+		}
+	}
+}
+
+func FuzzParseData(f *testing.F) {
+	f.Add([]byte(`{}`))
+	f.Add([]byte(`null`))
+	f.Add([]byte(`{"schemaVersion": 3}`))
+	f.Add([]byte(`{"ores": [{"name": "Iron Ore", "value": 2}], "alloys": [{"name": "Iron Bar", "value": 3000, "ingredients": [{"name": "Iron Ore", "amount": 1000}]}]}`))
+	f.Add([]byte(`{"schemaVersion": 2, "alloys": [{"name": "Bar", "value": -1, "ingredients": [{"name": "Bar", "amount": 0}]}]}`))
+	f.Fuzz(func(t *testing.T, b []byte) {
+		data, err := parseData(b)
+		if err != nil {
+			return
+		}
+		if data.SchemaVersion != currentSchemaVersion {
+			t.Errorf("loaded schema %d, want %d", data.SchemaVersion, currentSchemaVersion)
+		}
+		checkGameData(t, data)
+	})
+}
+
+func FuzzDecodeData(f *testing.F) {
+	f.Add([]byte("ores:\n  - name: Iron Ore\n    value: 2\nalloys:\n  - name: Iron Bar\n    value: 3000\n    ingredients:\n      - name: Iron Ore\n        amount: 1000\n"), uint8(YAMLData))
+	f.Add([]byte("~"), uint8(YAMLData))
+	f.Add([]byte("[[ores]]\nname = \"Iron Ore\"\nvalue = 2\n\n[[alloys]]\nname = \"Iron Bar\"\nvalue = 3000\n\n[[alloys.ingredients]]\nname = \"Iron Ore\"\namount = 1000\n"), uint8(TOMLData))
+	f.Add([]byte("schemaVersion = 0\n"), uint8(TOMLData))
+	f.Fuzz(func(t *testing.T, b []byte, format uint8) {
+		data, err := decodeData(b, DataFormat(format%3))
+		if err != nil {
+			return
+		}
+		checkGameData(t, data)
+	})
+}
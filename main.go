@@ -1,7 +1,63 @@
-package main
-
-func main() {
-	data := loadData()
-	app := NewApp(data)
-	app.Run()
-}
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// parseFlags reads the flags before the command. Launchers add flags of
+// their own when starting the app (macOS's -psn_..., for one), those are
+// reported and the app starts as usual rather than exiting.
+func parseFlags(args []string) (dataPath string, rest []string, err error) {
+	flags := flag.NewFlagSet("idle-planet-calc", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&dataPath, "data", "", "game data to use instead of the built in inventory (.json, .yaml or .toml)")
+	if err = flags.Parse(args); errors.Is(err, flag.ErrHelp) {
+		flags.SetOutput(os.Stderr)
+		flags.Usage()
+		os.Exit(0)
+	}
+	return dataPath, flags.Args(), err
+}
+
+func main() {
+	dataPath, args, err := parseFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ignoring flags: %s\n", err)
+		args = nil
+	}
+
+	data := loadData()
+	if dataPath != "" {
+		if data, err = readDataFile(dataPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if len(args) > 0 {
+		switch args[0] {
+		case "serve":
+			err = runServer(data, args[1:])
+		case "tui":
+			err = runTUI(data, args[1:])
+		case "sim":
+			err = runSim(data, args[1:])
+		case "diff":
+			err = runDiff(args[1:])
+		case "convert":
+			err = runConvert(args[1:])
+		default:
+			err = fmt.Errorf("unknown command: %s", args[0])
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	app := NewApp(data)
+	app.Run()
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	dataPath, args, err := parseFlags([]string{"-data", "inventory.yaml", "serve", "-addr", ":9000"})
+	if err != nil || dataPath != "inventory.yaml" || !slices.Equal(args, []string{"serve", "-addr", ":9000"}) {
+		t.Errorf("parsed %q, %q, %v", dataPath, args, err)
+	}
+	if _, _, err := parseFlags([]string{"-psn_0_1234567"}); err == nil {
+		t.Error("no error for a launcher flag")
+	}
+}
@@ -1,39 +1,52 @@
-package main
-
-type ItemType int
-
-const (
-	Ore ItemType = iota
-	Alloy
-	Item
-)
-
-var itemTypeName = map[ItemType]string{
-	Ore:   "Ore",
-	Alloy: "Alloy",
-	Item:  "Item",
-}
-
-func (it ItemType) String() string {
-	return itemTypeName[it]
-}
-
-type GameItem struct {
-	Name        string
-	Type        ItemType
-	Value       int
-	Ingredients []Ingredient
-}
-
-type Ingredient struct {
-	Item   GameItem
-	Value  int
-	Amount int
-}
-
-type ResultItem struct {
-	Amount      int
-	Name        string
-	Value       int
-	Ingredients []ResultItem
-}
+package main
+
+type ItemType int
+
+const (
+	Ore ItemType = iota
+	Alloy
+	Item
+)
+
+var itemTypeName = map[ItemType]string{
+	Ore:   "Ore",
+	Alloy: "Alloy",
+	Item:  "Item",
+}
+
+func (it ItemType) String() string {
+	return itemTypeName[it]
+}
+
+type GameItem struct {
+	Name        string
+	Type        ItemType
+	Value       int
+	Time        int // seconds to smelt or craft one, 0 when unknown
+	Ingredients []Ingredient
+	boms        *bomCache // shared by every item of the same game data
+}
+
+type Ingredient struct {
+	Item   GameItem
+	Value  int
+	Amount int
+	Group  string
+}
+
+type ResultItem struct {
+	Amount      int          `json:"amount"`
+	Name        string       `json:"name"`
+	Value       int          `json:"value"`
+	Group       string       `json:"group,omitempty"`
+	Ingredients []ResultItem `json:"ingredients,omitempty"`
+}
+
+type Bonuses struct {
+	CraftingEfficiency bool    `json:"craftingEfficiency"`
+	SmeltingEfficiency bool    `json:"smeltingEfficiency"`
+	CraftValBonus      float64 `json:"craftValBonus"`
+	SmeltValBonus      float64 `json:"smeltValBonus"`
+	UnderforgeBonus    float64 `json:"underforgeBonus"`
+	DormsBonus         float64 `json:"dormsBonus"`
+}
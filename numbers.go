@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+var letterExponents = map[string]int{
+	"k": 3,
+	"m": 6,
+	"b": 9,
+	"t": 12,
+}
+
+// suffixExponent returns the power of ten for a game style suffix, K, M, B
+// and T followed by aa, ab ... az, ba and so on, in either case.
+func suffixExponent(suffix string) (int, bool) {
+	suffix = strings.ToLower(suffix)
+	if exponent, found := letterExponents[suffix]; found {
+		return exponent, true
+	}
+	if len(suffix) != 2 || suffix[0] < 'a' || suffix[0] > 'z' || suffix[1] < 'a' || suffix[1] > 'z' {
+		return 0, false
+	}
+	return 15 + 3*(int(suffix[0]-'a')*26+int(suffix[1]-'a')), true
+}
+
+// parseAmount reads amounts such as "5T", "$1.5 aa", "2.5e12" or "12,000",
+// anything after the first word is ignored so "$5T by tonight" reads as 5T.
+// A suffix is only taken from a second word when there is nothing after it,
+// "$5 by tonight" is 5 rather than 5 by.
+func parseAmount(input string) (int, error) {
+	text := strings.TrimPrefix(strings.TrimSpace(input), "$")
+	text = strings.NewReplacer(",", "", "_", "").Replace(text)
+	if fields := strings.Fields(text); len(fields) > 1 {
+		text = fields[0]
+		if _, ok := suffixExponent(fields[1]); ok && len(fields) == 2 && strings.Trim(text, "0123456789.") == "" {
+			text += fields[1]
+		}
+	}
+
+	end := strings.IndexFunc(text, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if end < 0 {
+		end = len(text)
+	}
+	val, err := strconv.ParseFloat(text[:end], 64)
+	if err != nil {
+		return 0, fmt.Errorf("not an amount: %s", input)
+	}
+	if suffix := text[end:]; suffix != "" {
+		exponent, ok := suffixExponent(suffix)
+		if power, err := strconv.Atoi(suffix[1:]); !ok && err == nil && strings.EqualFold(suffix[:1], "e") {
+			exponent, ok = power, true
+		}
+		if !ok {
+			return 0, fmt.Errorf("unknown suffix: %s", suffix)
+		}
+		val *= math.Pow10(exponent)
+	}
+	// 0 with a huge exponent is 0 times infinity, which isn't a number
+	if math.IsNaN(val) {
+		return 0, fmt.Errorf("not an amount: %s", input)
+	}
+	if val = math.Round(val); math.IsInf(val, 0) || val >= math.MaxInt64 {
+		return 0, fmt.Errorf("amount too large: %s", input)
+	}
+	return int(val), nil
+}
+
+type NumberFormat int
+
+const (
+	FullNumbers NumberFormat = iota
+	SuffixNumbers
+	ScientificNumbers
+)
+
+var numberFormatName = map[NumberFormat]string{
+	FullNumbers:       "Full",
+	SuffixNumbers:     "Game suffix",
+	ScientificNumbers: "Scientific",
+}
+
+func (f NumberFormat) String() string {
+	return numberFormatName[f]
+}
+
+// numberFormat is how amounts and values are shown, picked in Settings
+var numberFormat = FullNumbers
+
+// suffixForExponent is the reverse of suffixExponent for multiples of 3
+func suffixForExponent(exponent int) string {
+	if exponent < 15 {
+		return strings.ToUpper([]string{"", "k", "m", "b", "t"}[exponent/3])
+	}
+	index := (exponent - 15) / 3
+	return string([]byte{byte('a' + index/26), byte('a' + index%26)})
+}
+
+// formatMantissa shows up to 2 decimal places, dropping trailing zeros
+func formatMantissa(val float64) string {
+	text := strconv.FormatFloat(val, 'f', 2, 64)
+	return strings.TrimSuffix(strings.TrimRight(text, "0"), ".")
+}
+
+// splitNumber splits val into a mantissa rounded to 2 decimal places and a
+// power of ten, the power a multiple of step.
+func splitNumber(val int, step int) (float64, int) {
+	exponent := (len(strconv.Itoa(val)) - 1) / step * step
+	mantissa := math.Round(float64(val)/math.Pow10(exponent)*100) / 100
+	if mantissa >= math.Pow10(step) {
+		mantissa /= math.Pow10(step)
+		exponent += step
+	}
+	return mantissa, exponent
+}
+
+// formatNumber shows val in the chosen number format, values under 1,000
+// are always shown in full.
+func formatNumber(val int) string {
+	if (val > -1000 && val < 1000) || numberFormat == FullNumbers {
+		return humanize.Comma(int64(val))
+	}
+	if val < 0 {
+		// the smallest int has no positive, the largest shortens the same
+		return "-" + formatNumber(-max(val, -math.MaxInt64))
+	}
+	if numberFormat == ScientificNumbers {
+		mantissa, exponent := splitNumber(val, 1)
+		return fmt.Sprintf("%se%d", formatMantissa(mantissa), exponent)
+	}
+	mantissa, exponent := splitNumber(val, 3)
+	return formatMantissa(mantissa) + suffixForExponent(exponent)
+}
+
+func formatMoney(val int) string {
+	return "$" + formatNumber(val)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	for _, test := range []struct {
+		input string
+		want  int
+	}{
+		{"12,000", 12000},
+		{"5T", 5e12},
+		{"$1.5 aa", 1.5e15},
+		{"1.5AA", 1.5e15},
+		{"2 Ab", 2e18},
+		{"2.5e12", 2.5e12},
+		{"$5T by tonight", 5e12},
+		{"$5 by tonight", 5},
+	} {
+		if got, err := parseAmount(test.input); err != nil || got != test.want {
+			t.Errorf("parseAmount(%q) = %d, %v, want %d", test.input, got, err, test.want)
+		}
+	}
+	for _, input := range []string{"", "five", "5q", "1e30", "0e400", "0e999", "0zz"} {
+		if got, err := parseAmount(input); err == nil {
+			t.Errorf("parseAmount(%q) = %d, want an error", input, got)
+		}
+	}
+}
+
+func TestFormatNumberExtremes(t *testing.T) {
+	t.Cleanup(func() { numberFormat = FullNumbers })
+	for format, want := range map[NumberFormat][2]string{
+		FullNumbers:       {"-9,223,372,036,854,775,808", "9,223,372,036,854,775,807"},
+		SuffixNumbers:     {"-9.22ab", "9.22ab"},
+		ScientificNumbers: {"-9.22e18", "9.22e18"},
+	} {
+		numberFormat = format
+		if got := [2]string{formatNumber(math.MinInt64), formatNumber(math.MaxInt64)}; got != want {
+			t.Errorf("%s: %v, want %v", format, got, want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"maps"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// refreshOrderGroups shows a heading on the first order of each group
+func (a *App) refreshOrderGroups() {
+	previous := ""
+	for _, o := range a.orderContainer.Objects {
+		order := o.(*Order)
+		heading := ""
+		if order.group != previous {
+			heading = order.group
+			if heading == "" {
+				heading = tr("Ungrouped")
+			}
+		}
+		previous = order.group
+		if order.heading != heading {
+			order.SetHeading(heading)
+		}
+	}
+	a.orderContainer.Refresh()
+}
+
+func (a *App) getOrderGroups() []string {
+	groups := make(map[string]bool)
+	for _, o := range a.orderContainer.Objects {
+		if group := o.(*Order).group; group != "" {
+			groups[group] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(groups))
+}
+
+func (a *App) setOrderIndex(item *Order, index int) {
+	rows := slices.DeleteFunc(a.orderContainer.Objects, func(o fyne.CanvasObject) bool {
+		return o == item
+	})
+	a.orderContainer.Objects = slices.Insert(rows, index, fyne.CanvasObject(item))
+}
+
+// moveOrder moves an order by a number of rows, taking the group of the
+// orders it lands next to so groups stay together. Moving by no rows lays
+// the orders out again, putting back a row dragged less than its height.
+func (a *App) moveOrder(item *Order, rows int) {
+	index := slices.Index(a.orderContainer.Objects, fyne.CanvasObject(item))
+	target := max(0, min(len(a.orderContainer.Objects)-1, index+rows))
+	if index < 0 || index == target {
+		a.refreshOrderGroups()
+		return
+	}
+
+	before := a.getOrderRows()
+	a.setOrderIndex(item, target)
+	if target > 0 {
+		item.group = a.orderContainer.Objects[target-1].(*Order).group
+	} else {
+		item.group = a.orderContainer.Objects[1].(*Order).group
+	}
+	a.refreshOrderGroups()
+	a.recordOrderRows(before)
+	a.inputsChanged()
+}
+
+func (a *App) duplicateOrder(item *Order) {
+	before := a.getOrderRows()
+	index := slices.Index(a.orderContainer.Objects, fyne.CanvasObject(item))
+	duplicate := a.addOrder(Ingredient{
+		Item:   item.orderItem,
+		Amount: item.amount,
+		Group:  item.group,
+	})
+	a.setOrderIndex(duplicate, index+1)
+	a.refreshOrderGroups()
+	a.recordOrderRows(before)
+}
+
+// setOrderGroup moves an order to the end of its new group, or the end of
+// the list for a new group.
+func (a *App) setOrderGroup(item *Order, group string) {
+	if item.group == group {
+		return
+	}
+	before := a.getOrderRows()
+	item.group = group
+	target := len(a.orderContainer.Objects) - 1
+	for index, o := range a.orderContainer.Objects {
+		if o != item && o.(*Order).group == group {
+			target = index
+		}
+	}
+	if index := slices.Index(a.orderContainer.Objects, fyne.CanvasObject(item)); index > target {
+		target++
+	}
+	a.setOrderIndex(item, target)
+	a.refreshOrderGroups()
+	a.recordOrderRows(before)
+	a.inputsChanged()
+}
+
+func (a *App) groupHandler(item *Order) {
+	group := widget.NewSelectEntry(a.getOrderGroups())
+	group.SetText(item.group)
+	group.SetPlaceHolder(tr("No group"))
+	dialog.ShowForm(tr("Group"), tr("Set"), tr("Cancel"), []*widget.FormItem{
+		widget.NewFormItem(tr("Group"), group),
+	}, func(ok bool) {
+		if ok {
+			a.setOrderGroup(item, group.Text)
+		}
+	}, a.mainWindow)
+}
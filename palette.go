@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const paletteSize = 8
+
+type paletteCommand struct {
+	label  string
+	action func()
+}
+
+func (a *App) getCommands() []paletteCommand {
+	return []paletteCommand{
+		{tr("Add order"), a.newOrderHandler},
+		{tr("Calculate"), a.calcResultsHandler},
+		{tr("Bonuses"), a.bonusesHandler},
+		{tr("Export"), a.exportHandler},
+		{tr("Plans"), a.plansHandler},
+		{tr("Filter results"), a.focusFilter},
+		{tr("Undo"), a.undo},
+		{tr("Redo"), a.redo},
+		{tr("Cash goal..."), a.goalHandler},
+		{tr("Simulate..."), a.simHandler},
+		{tr("Compare bonuses..."), a.compareHandler},
+		{tr("Inventory diff..."), a.diffHandler},
+		{tr("Edit game data..."), a.editorHandler},
+		{tr("Settings..."), a.settingsHandler},
+		{tr("About"), a.aboutHandler},
+	}
+}
+
+// getPaletteCommands matches query against the commands, and when it reads
+// as an order such as "fusion reactor 3" against the items to add, best
+// match first.
+func (a *App) getPaletteCommands(query string) []paletteCommand {
+	type scoredCommand struct {
+		paletteCommand
+		score int
+	}
+	matches := make([]scoredCommand, 0)
+	for _, command := range a.getCommands() {
+		if score, ok := matchScore(query, command.label); ok || query == "" {
+			matches = append(matches, scoredCommand{command, score})
+		}
+	}
+	if name, amount := parseOrderLine(query); name != "" {
+		for _, item := range a.itemList {
+			if score, ok := matchScore(name, itemName(item)); ok {
+				matches = append(matches, scoredCommand{paletteCommand{
+					label: fmt.Sprintf(tr("Add %s x %s"), formatNumber(amount), itemName(item)),
+					action: func() {
+						a.addOrders([]Ingredient{{Item: a.data[item], Amount: amount}}, false)
+					},
+				}, score})
+			}
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b scoredCommand) int {
+		return a.score - b.score
+	})
+
+	commands := make([]paletteCommand, 0)
+	for _, match := range matches[:min(len(matches), paletteSize)] {
+		commands = append(commands, match.paletteCommand)
+	}
+	return commands
+}
+
+func (a *App) paletteHandler() {
+	var paletteDialog dialog.Dialog
+	var commands []paletteCommand
+
+	input := widget.NewEntry()
+	input.SetPlaceHolder(tr("Type a command or an order like \"fusion reactor 3\""))
+	list := widget.NewList(
+		func() int {
+			return len(commands)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("template")
+		},
+		func(id widget.ListItemID, co fyne.CanvasObject) {
+			co.(*widget.Label).SetText(commands[id].label)
+		},
+	)
+	run := func(command paletteCommand) {
+		paletteDialog.Hide()
+		command.action()
+	}
+	list.OnSelected = func(id widget.ListItemID) {
+		run(commands[id])
+	}
+	input.OnChanged = func(text string) {
+		commands = a.getPaletteCommands(text)
+		list.UnselectAll()
+		list.Refresh()
+	}
+	input.OnSubmitted = func(string) {
+		if len(commands) > 0 {
+			run(commands[0])
+		}
+	}
+	input.OnChanged("")
+
+	paletteDialog = dialog.NewCustom(tr("Commands"), tr("Close"),
+		container.NewBorder(input, nil, nil, nil, list), a.mainWindow)
+	paletteDialog.Resize(fyne.NewSize(
+		min(450, a.mainWindow.Canvas().Size().Width-theme.Padding()*4),
+		min(420, a.mainWindow.Canvas().Size().Height-theme.Padding()*4)))
+	paletteDialog.Show()
+	a.mainWindow.Canvas().Focus(input)
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+type PlanObjective int
+
+const (
+	LeastOre PlanObjective = iota
+	FewestCraftHours
+)
+
+var planObjectiveName = map[PlanObjective]string{
+	LeastOre:         "Least ore",
+	FewestCraftHours: "Fewest craft hours",
+}
+
+func (o PlanObjective) String() string {
+	return planObjectiveName[o]
+}
+
+type planCandidate struct {
+	item  GameItem
+	value int
+	cost  float64
+}
+
+type goalPlan struct {
+	Orders    []Ingredient
+	Value     int
+	Cost      float64
+	Objective PlanObjective
+}
+
+func (p goalPlan) Report() string {
+	lines := make([]string, 0)
+	for _, o := range p.Orders {
+		lines = append(lines, fmt.Sprintf("%s x %s", formatNumber(o.Amount), itemName(o.Item.Name)))
+	}
+	lines = append(lines, fmt.Sprintf(tr("Value: $%s"), formatNumber(p.Value)))
+	if p.Objective == FewestCraftHours {
+		lines = append(lines, fmt.Sprintf(tr("Craft time: %s"), time.Duration(p.Cost*float64(time.Second)).Round(time.Second)))
+	} else {
+		lines = append(lines, fmt.Sprintf(tr("Raw ore: %s"), formatNumber(int(p.Cost))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// getOreCost is the raw ore needed for one of item
+func (b Bonuses) getOreCost(item GameItem) float64 {
+	total := 0
+	for _, i := range b.getIngredients(item) {
+		if i.Item.Type == Ore {
+			total += i.Amount
+		}
+	}
+	return float64(total)
+}
+
+// getCraftTime is the seconds spent smelting and crafting one of item and
+// everything that goes into it, false when any of the timings are unknown.
+func (b Bonuses) getCraftTime(item GameItem) (float64, bool) {
+	if item.Type == Ore {
+		return 0, true
+	}
+	if item.Time <= 0 {
+		return 0, false
+	}
+	total := float64(item.Time)
+	for _, i := range item.Ingredients {
+		ingredientTime, ok := b.getCraftTime(i.Item)
+		if !ok {
+			return 0, false
+		}
+		total += ingredientTime * float64(b.getBonusedMaterialAmount(item.Type, i.Amount))
+	}
+	return total, true
+}
+
+func (b Bonuses) getPlanCandidates(objective PlanObjective, data map[string]GameItem, names []string) []planCandidate {
+	candidates := make([]planCandidate, 0)
+	for _, name := range names {
+		item := data[name]
+		value := b.getBonusedValue(item.Type, item.Value)
+		if value <= 0 {
+			continue
+		}
+		var cost float64
+		if objective == FewestCraftHours {
+			craftTime, ok := b.getCraftTime(item)
+			if !ok {
+				continue
+			}
+			cost = craftTime
+		} else {
+			cost = b.getOreCost(item)
+		}
+		if cost <= 0 {
+			continue
+		}
+		candidates = append(candidates, planCandidate{item, value, cost})
+	}
+	slices.SortStableFunc(candidates, func(x, y planCandidate) int {
+		switch rx, ry := x.cost/float64(x.value), y.cost/float64(y.value); {
+		case rx < ry:
+			return -1
+		case rx > ry:
+			return 1
+		}
+		return strings.Compare(x.item.Name, y.item.Name)
+	})
+	return candidates
+}
+
+// planGoal proposes orders worth at least goal once sold, filling up with
+// the cheapest item per dollar and topping off the remainder with whichever
+// item covers it for the least extra cost.
+func (b Bonuses) planGoal(goal int, objective PlanObjective, data map[string]GameItem, names []string) (goalPlan, error) {
+	plan := goalPlan{Orders: make([]Ingredient, 0), Objective: objective}
+	if goal <= 0 {
+		return plan, errors.New("the goal has to be more than $0")
+	}
+	candidates := b.getPlanCandidates(objective, data, names)
+	if len(candidates) == 0 {
+		if objective == FewestCraftHours {
+			return plan, errors.New("no craft timings are known, add a time to the items in the game data")
+		}
+		return plan, errors.New("nothing to sell")
+	}
+
+	add := func(c planCandidate, amount int) {
+		if amount <= 0 {
+			return
+		}
+		plan.Value += c.value * amount
+		plan.Cost += c.cost * float64(amount)
+		for index, o := range plan.Orders {
+			if o.Item.Name == c.item.Name {
+				plan.Orders[index].Amount += amount
+				return
+			}
+		}
+		plan.Orders = append(plan.Orders, Ingredient{Item: c.item, Amount: amount})
+	}
+
+	best := candidates[0]
+	add(best, goal/best.value)
+	if remainder := goal - plan.Value; remainder > 0 {
+		fill, fillAmount, fillCost := best, 0, math.Inf(1)
+		for _, c := range candidates {
+			amount := (remainder + c.value - 1) / c.value
+			if cost := c.cost * float64(amount); cost < fillCost {
+				fill, fillAmount, fillCost = c, amount, cost
+			}
+		}
+		add(fill, fillAmount)
+	}
+	return plan, nil
+}
+
+// hasCraftTimes is true when at least one alloy or item has all its
+// timings, planning for fewest craft hours is pointless otherwise.
+func (b Bonuses) hasCraftTimes(data map[string]GameItem) bool {
+	for _, item := range data {
+		if _, ok := b.getCraftTime(item); ok && item.Type != Ore {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) goalHandler() {
+	var goalDialog dialog.Dialog
+	var plan goalPlan
+
+	objectives := []string{tr(LeastOre.String())}
+	if a.hasCraftTimes(a.data) {
+		objectives = append(objectives, tr(FewestCraftHours.String()))
+	}
+	goal := widget.NewEntry()
+	goal.SetPlaceHolder("$5T")
+	objective := widget.NewSelect(objectives, nil)
+	objective.SetSelectedIndex(0)
+	preview := widget.NewLabel("")
+	preview.Wrapping = fyne.TextWrapWord
+	useButton := widget.NewButtonWithIcon(tr("Use orders"), theme.ConfirmIcon(), func() {
+		goalDialog.Hide()
+		a.addOrders(plan.Orders, true)
+		a.calcResultsHandler()
+	})
+	useButton.Disable()
+
+	update := func() {
+		useButton.Disable()
+		amount, err := parseAmount(goal.Text)
+		if goal.Text == "" {
+			preview.SetText("")
+			return
+		}
+		if err == nil {
+			plan, err = a.planGoal(amount, PlanObjective(objective.SelectedIndex()), a.data, a.itemList)
+		}
+		if err != nil {
+			preview.SetText(err.Error())
+			return
+		}
+		preview.SetText(plan.Report())
+		useButton.Enable()
+	}
+	goal.OnChanged = func(string) { update() }
+	objective.OnChanged = func(string) { update() }
+
+	form := widget.NewForm(widget.NewFormItem(tr("Goal"), goal))
+	if len(objectives) > 1 {
+		form.AppendItem(widget.NewFormItem(tr("Plan for"), objective))
+	}
+	goalDialog = dialog.NewCustom(tr("Cash goal"), tr("Close"), container.NewVBox(
+		form,
+		preview,
+		useButton,
+	), a.mainWindow)
+	goalDialog.Resize(goalDialog.MinSize().AddWidthHeight(100, 0))
+	goalDialog.Show()
+}
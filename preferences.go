@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const appID = "phanteh.idle.planet.calc"
+
+// bonusPreferences is the part of fyne.Preferences used to store bonuses, so
+// front ends without a Fyne app can share them.
+type bonusPreferences interface {
+	BoolWithFallback(key string, fallback bool) bool
+	FloatWithFallback(key string, fallback float64) float64
+	SetBool(key string, value bool)
+	SetFloat(key string, value float64)
+}
+
+func loadBonuses(p bonusPreferences) Bonuses {
+	return Bonuses{
+		SmeltingEfficiency: p.BoolWithFallback("smeltingEfficiency", false),
+		CraftingEfficiency: p.BoolWithFallback("craftingEfficiency", false),
+		CraftValBonus:      p.FloatWithFallback("craftValBonus", 1.0),
+		SmeltValBonus:      p.FloatWithFallback("smeltValBonus", 1.0),
+		DormsBonus:         p.FloatWithFallback("dormsBonus", 1.0),
+		UnderforgeBonus:    p.FloatWithFallback("forgeBonus", 1.0),
+	}
+}
+
+func saveBonuses(p bonusPreferences, b Bonuses) {
+	p.SetBool("smeltingEfficiency", b.SmeltingEfficiency)
+	p.SetBool("craftingEfficiency", b.CraftingEfficiency)
+	p.SetFloat("craftValBonus", b.CraftValBonus)
+	p.SetFloat("smeltValBonus", b.SmeltValBonus)
+	p.SetFloat("dormsBonus", b.DormsBonus)
+	p.SetFloat("forgeBonus", b.UnderforgeBonus)
+}
+
+// filePreferences reads and writes the preferences file of the desktop app
+// directly, Fyne only saves its preferences from a running driver.
+type filePreferences struct {
+	path   string
+	values map[string]any
+}
+
+// getPreferencesPath mirrors where the Fyne desktop drivers keep preferences
+func getPreferencesPath() string {
+	home, _ := os.UserHomeDir()
+	var root string
+	switch runtime.GOOS {
+	case "darwin":
+		root = filepath.Join(home, "Library", "Preferences")
+	case "windows":
+		root = filepath.Join(home, "AppData", "Roaming")
+	default:
+		root, _ = os.UserConfigDir()
+	}
+	return filepath.Join(root, "fyne", appID, "preferences.json")
+}
+
+func openFilePreferences(path string) (*filePreferences, error) {
+	p := &filePreferences{
+		path:   path,
+		values: make(map[string]any),
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return p, nil
+	}
+	return p, json.Unmarshal(data, &p.values)
+}
+
+func (p *filePreferences) BoolWithFallback(key string, fallback bool) bool {
+	if val, ok := p.values[key].(bool); ok {
+		return val
+	}
+	return fallback
+}
+
+func (p *filePreferences) FloatWithFallback(key string, fallback float64) float64 {
+	if val, ok := p.values[key].(float64); ok {
+		return val
+	}
+	return fallback
+}
+
+func (p *filePreferences) StringWithFallback(key string, fallback string) string {
+	if val, ok := p.values[key].(string); ok {
+		return val
+	}
+	return fallback
+}
+
+func (p *filePreferences) SetBool(key string, value bool) {
+	p.values[key] = value
+}
+
+func (p *filePreferences) SetFloat(key string, value float64) {
+	p.values[key] = value
+}
+
+func (p *filePreferences) Save() error {
+	if err := os.MkdirAll(filepath.Dir(p.path), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(p.values)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p.path, data, 0600)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+//go:embed web
+var webFiles embed.FS
+
+type Server struct {
+	data map[string]GameItem
+}
+
+type serverItem struct {
+	Name        string             `json:"name"`
+	Type        string             `json:"type"`
+	Value       int                `json:"value"`
+	Ingredients []serverIngredient `json:"ingredients,omitempty"`
+}
+
+type serverIngredient struct {
+	Name   string `json:"name"`
+	Amount int    `json:"amount"`
+}
+
+type serverOrder struct {
+	Name   string `json:"name"`
+	Amount int    `json:"amount"`
+	Group  string `json:"group,omitempty"`
+}
+
+// maxRequestBody is well past any real set of orders
+const maxRequestBody = 1 << 20
+
+type calculateRequest struct {
+	Orders  []serverOrder `json:"orders"`
+	Bonuses Bonuses       `json:"bonuses"`
+}
+
+func NewServer(data *jsonGameData) *Server {
+	return &Server{
+		data: getGameData(data),
+	}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/items", s.itemsHandler)
+	mux.HandleFunc("GET /api/items/{name}", s.itemHandler)
+	mux.HandleFunc("POST /api/calculate", s.calculateHandler)
+	web, _ := fs.Sub(webFiles, "web")
+	mux.Handle("GET /", http.FileServerFS(web))
+	return mux
+}
+
+func runServer(data *jsonGameData, args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	flags.Parse(args)
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           NewServer(data).Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       30 * time.Second,
+	}
+	log.Printf("listening on %s", *addr)
+	return server.ListenAndServe()
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func newServerItem(item GameItem) serverItem {
+	result := serverItem{
+		Name:        item.Name,
+		Type:        item.Type.String(),
+		Value:       item.Value,
+		Ingredients: make([]serverIngredient, 0),
+	}
+	for _, i := range item.Ingredients {
+		result.Ingredients = append(result.Ingredients, serverIngredient{
+			Name:   i.Item.Name,
+			Amount: i.Amount,
+		})
+	}
+	return result
+}
+
+// itemsHandler lists every item, optionally filtered with ?type=ore|alloy|item
+func (s *Server) itemsHandler(w http.ResponseWriter, r *http.Request) {
+	itemType := r.URL.Query().Get("type")
+	items := slices.SortedFunc(maps.Values(s.data), func(a, b GameItem) int {
+		if a.Type != b.Type {
+			return int(a.Type) - int(b.Type)
+		}
+		return a.Value - b.Value
+	})
+
+	result := make([]serverItem, 0)
+	for _, item := range items {
+		if itemType != "" && !strings.EqualFold(itemType, item.Type.String()) {
+			continue
+		}
+		result = append(result, newServerItem(item))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) itemHandler(w http.ResponseWriter, r *http.Request) {
+	item, found := s.data[r.PathValue("name")]
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown item: %s", r.PathValue("name")))
+		return
+	}
+	writeJSON(w, http.StatusOK, newServerItem(item))
+}
+
+// calculateHandler returns the bill of materials for a set of orders, any
+// bonuses left out of the request default to no bonus and multipliers have
+// to be above 0.
+func (s *Server) calculateHandler(w http.ResponseWriter, r *http.Request) {
+	request := calculateRequest{
+		Bonuses: NewBonuses(),
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&request); err != nil {
+		status := http.StatusBadRequest
+		if tooLarge := new(http.MaxBytesError); errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		writeError(w, status, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	if err := checkBonuses("request", request.Bonuses); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	orders := make([]Ingredient, 0)
+	for _, o := range request.Orders {
+		item, found := s.data[o.Name]
+		if !found {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("unknown item: %s", o.Name))
+			return
+		}
+		if o.Amount <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid amount for %s: %d", o.Name, o.Amount))
+			return
+		}
+		orders = append(orders, Ingredient{
+			Item:   item,
+			Amount: o.Amount,
+			Group:  o.Group,
+		})
+	}
+
+	results, summary := request.Bonuses.calculate(orders)
+	writeJSON(w, http.StatusOK, newExportData(results, summary, request.Bonuses))
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCalculateRequest(t *testing.T) {
+	handler := NewServer(loadData()).Handler()
+	for _, test := range []struct {
+		body   string
+		status int
+	}{
+		{`{"orders": [{"name": "Copper Bar", "amount": 3}]}`, http.StatusOK},
+		{`{"orders": [{"name": "Copper Bar", "amount": 3}], "bonuses": {"smeltValBonus": 1.5}}`, http.StatusOK},
+		{`{"orders": [{"name": "Copper Bar", "amount": 3}], "bonuses": {"dormsBonus": 0}}`, http.StatusBadRequest},
+		{`{"orders": [{"name": "Copper Bar", "amount": 3}], "bonuses": {"craftValBonus": -2}}`, http.StatusBadRequest},
+		{`{"orders": [{"name": "Copper Bar", "amount": 0}]}`, http.StatusBadRequest},
+		{`{"orders": [{"name": "Unobtainium", "amount": 1}]}`, http.StatusBadRequest},
+		{`{"orders": [` + strings.Repeat(`{"name": "Copper Bar", "amount": 1}, `, maxRequestBody/30) + `]}`, http.StatusRequestEntityTooLarge},
+	} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/api/calculate", strings.NewReader(test.body)))
+		if recorder.Code != test.status {
+			t.Errorf("%.100s: status %d, want %d: %.100s", test.body, recorder.Code, test.status, recorder.Body)
+		}
+	}
+}
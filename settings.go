@@ -0,0 +1,96 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/validation"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+func (a *App) settingsHandler() {
+	historyDepth := widget.NewEntry()
+	historyDepth.SetText(strconv.Itoa(a.history.depth))
+	historyDepth.Validator = validation.NewRegexp("^[1-9][0-9]*$", tr("Whole numbers only please"))
+	historyDepth.OnChanged = func(input string) {
+		depth, err := strconv.Atoi(input)
+		if err != nil || depth < 1 {
+			return
+		}
+		a.history.SetDepth(depth)
+		a.app.Preferences().SetInt("historyDepth", depth)
+	}
+
+	// language names are shown in their own language, System follows the OS
+	languageCodes := append([]string{""}, slices.Sorted(maps.Keys(languageNames))...)
+	languageOptions := []string{tr("System")}
+	for _, code := range languageCodes[1:] {
+		languageOptions = append(languageOptions, languageNames[code])
+	}
+	languageSelect := widget.NewSelect(languageOptions, nil)
+	languageSelect.SetSelectedIndex(slices.Index(languageCodes, a.app.Preferences().String("language")))
+	languageSelect.OnChanged = func(string) {
+		a.app.Preferences().SetString("language", languageCodes[languageSelect.SelectedIndex()])
+		dialog.ShowInformation(tr("Language"), tr("The new language is used next time the app starts"), a.mainWindow)
+	}
+
+	formats := []string{tr(FullNumbers.String()), tr(SuffixNumbers.String()), tr(ScientificNumbers.String())}
+	formatSelect := widget.NewSelect(formats, nil)
+	formatSelect.SetSelectedIndex(int(numberFormat))
+	formatSelect.OnChanged = func(string) {
+		numberFormat = NumberFormat(formatSelect.SelectedIndex())
+		a.app.Preferences().SetInt("numberFormat", int(numberFormat))
+		a.displayResults(a.results)
+	}
+
+	variants := []string{tr(SystemVariant.String()), tr(LightVariant.String()), tr(DarkVariant.String())}
+	variantSelect := widget.NewSelect(variants, nil)
+	variantSelect.SetSelectedIndex(a.app.Preferences().IntWithFallback("themeVariant", int(SystemVariant)))
+	variantSelect.OnChanged = func(string) {
+		a.app.Preferences().SetInt("themeVariant", variantSelect.SelectedIndex())
+		a.applyTheme()
+	}
+
+	accentColors := append([]string{""}, theme.PrimaryColorNames()...)
+	accents := []string{tr("System")}
+	for _, name := range accentColors[1:] {
+		accents = append(accents, tr(strings.ToUpper(name[:1])+name[1:]))
+	}
+	accentSelect := widget.NewSelect(accents, nil)
+	accentSelect.SetSelectedIndex(max(0, slices.Index(accentColors, a.app.Preferences().String("accentColor"))))
+	accentSelect.OnChanged = func(string) {
+		a.app.Preferences().SetString("accentColor", accentColors[accentSelect.SelectedIndex()])
+		a.applyTheme()
+	}
+
+	iconDir := widget.NewLabel(a.icons.overrideDir)
+	iconDir.Wrapping = fyne.TextWrapBreak
+
+	var settingsDialog dialog.Dialog
+	exportButton := widget.NewButtonWithIcon(tr("Export settings"), theme.UploadIcon(), a.exportSettingsHandler)
+	importButton := widget.NewButtonWithIcon(tr("Import settings"), theme.DownloadIcon(), func() {
+		settingsDialog.Hide()
+		a.importSettingsHandler()
+	})
+
+	settingsDialog = dialog.NewCustom(tr("Settings"), tr("Close"), container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem(tr("Language"), languageSelect),
+			widget.NewFormItem(tr("Theme"), variantSelect),
+			widget.NewFormItem(tr("Accent"), accentSelect),
+			widget.NewFormItem(tr("Numbers"), formatSelect),
+			widget.NewFormItem(tr("Undo depth"), historyDepth),
+			widget.NewFormItem(tr("Icon folder"), iconDir),
+		),
+		container.NewHBox(exportButton, layout.NewSpacer(), importButton),
+	), a.mainWindow)
+	settingsDialog.Resize(settingsDialog.MinSize().AddWidthHeight(60, 0))
+	settingsDialog.Show()
+}
@@ -0,0 +1,468 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/validation"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	defaultSimTime = time.Minute
+	simMaxTicks    = 20000
+	simSamples     = 200
+)
+
+type SimStrategy int
+
+const (
+	SellOre SimStrategy = iota
+	SmeltBars
+	CraftItems
+)
+
+var simStrategyName = map[SimStrategy]string{
+	SellOre:    "Sell ore",
+	SmeltBars:  "Smelt bars",
+	CraftItems: "Craft items",
+}
+
+var simStrategyFlag = map[string]SimStrategy{
+	"sell":  SellOre,
+	"smelt": SmeltBars,
+	"craft": CraftItems,
+}
+
+func (s SimStrategy) String() string {
+	return simStrategyName[s]
+}
+
+type SimConfig struct {
+	MiningRates map[string]float64 // ore mined per second
+	Smelters    int
+	Crafters    int
+	DefaultTime time.Duration // used for anything without a time in the game data
+	Duration    time.Duration
+	Target      int
+}
+
+func (c SimConfig) check() error {
+	if c.Smelters < 0 || c.Crafters < 0 {
+		return errors.New("smelters and crafters can't be negative")
+	}
+	if c.Duration <= 0 {
+		return errors.New("the time to simulate has to be more than 0")
+	}
+	return nil
+}
+
+type SimSample struct {
+	Time time.Duration
+	Cash int
+}
+
+type SimResult struct {
+	Strategy      SimStrategy
+	Samples       []SimSample
+	TargetReached bool
+	TargetTime    time.Duration
+}
+
+type simJob struct {
+	item      GameItem
+	remaining time.Duration
+}
+
+type simulation struct {
+	Bonuses
+	config   SimConfig
+	strategy SimStrategy
+	data     map[string]GameItem
+	recipes  []GameItem
+	used     map[string]bool
+	stock    map[string]float64
+	cash     float64
+	smelters []*simJob
+	crafters []*simJob
+}
+
+// simulate steps through config.Duration mining ore and keeping every free
+// smelter and crafter busy with the most valuable recipe per second that
+// the stock allows, selling whatever the strategy doesn't hold on to. A
+// negative number of slots counts as none.
+func (b Bonuses) simulate(config SimConfig, strategy SimStrategy, data map[string]GameItem) SimResult {
+	s := &simulation{
+		Bonuses:  b,
+		config:   config,
+		strategy: strategy,
+		data:     data,
+		recipes:  make([]GameItem, 0),
+		used:     make(map[string]bool),
+		stock:    make(map[string]float64),
+		smelters: make([]*simJob, max(0, config.Smelters)),
+		crafters: make([]*simJob, max(0, config.Crafters)),
+	}
+	for _, item := range data {
+		if item.Type == Ore || len(item.Ingredients) == 0 || !s.canMine(item) {
+			continue
+		}
+		if item.Type == Item && strategy != CraftItems || strategy == SellOre {
+			continue
+		}
+		s.recipes = append(s.recipes, item)
+		for _, i := range item.Ingredients {
+			s.used[i.Item.Name] = true
+		}
+	}
+	slices.SortFunc(s.recipes, func(x, y GameItem) int {
+		rx := float64(b.getBonusedValue(x.Type, x.Value)) / s.getDuration(x).Seconds()
+		ry := float64(b.getBonusedValue(y.Type, y.Value)) / s.getDuration(y).Seconds()
+		switch {
+		case rx > ry:
+			return -1
+		case rx < ry:
+			return 1
+		}
+		return strings.Compare(x.Name, y.Name)
+	})
+
+	result := SimResult{Strategy: strategy, Samples: make([]SimSample, 0)}
+	tick := max(time.Second, config.Duration/simMaxTicks)
+	sampleEvery := max(tick, config.Duration/simSamples)
+	result.Samples = append(result.Samples, SimSample{})
+	for now := time.Duration(0); now < config.Duration; {
+		s.mine(tick)
+		s.work(s.smelters, Alloy, tick)
+		s.work(s.crafters, Item, tick)
+		s.sellOre()
+		now += tick
+
+		cash := int(s.cash)
+		if !result.TargetReached && config.Target > 0 && cash >= config.Target {
+			result.TargetReached = true
+			result.TargetTime = now
+		}
+		if now%sampleEvery == 0 || now >= config.Duration {
+			result.Samples = append(result.Samples, SimSample{now, cash})
+		}
+	}
+	return result
+}
+
+func (s *simulation) getDuration(item GameItem) time.Duration {
+	if item.Time > 0 {
+		return time.Duration(item.Time) * time.Second
+	}
+	return max(time.Second, s.config.DefaultTime)
+}
+
+func (s *simulation) mine(tick time.Duration) {
+	for ore, rate := range s.config.MiningRates {
+		s.stock[ore] += rate * tick.Seconds()
+	}
+}
+
+// sellOre sells any ore nothing is going to be made from
+func (s *simulation) sellOre() {
+	for ore := range s.config.MiningRates {
+		if s.used[ore] || s.stock[ore] <= 0 {
+			continue
+		}
+		s.cash += s.stock[ore] * float64(s.getBonusedValue(Ore, s.data[ore].Value))
+		s.stock[ore] = 0
+	}
+}
+
+func (s *simulation) work(slots []*simJob, itemType ItemType, tick time.Duration) {
+	for index := range slots {
+		budget := tick
+		for budget > 0 {
+			if slots[index] == nil {
+				if slots[index] = s.start(itemType); slots[index] == nil {
+					break
+				}
+			}
+			job := slots[index]
+			if job.remaining > budget {
+				job.remaining -= budget
+				break
+			}
+			budget -= job.remaining
+			s.finish(job.item)
+			slots[index] = nil
+		}
+	}
+}
+
+// start picks the next job for a free slot, when crafting items the
+// ingredients the best items are short of come first.
+func (s *simulation) start(itemType ItemType) *simJob {
+	if s.strategy == CraftItems {
+		for _, recipe := range s.recipes {
+			if recipe.Type != Item {
+				continue
+			}
+			for _, i := range recipe.Ingredients {
+				ingredient := s.data[i.Item.Name]
+				if ingredient.Type != itemType || !s.used[ingredient.Name] ||
+					s.stock[ingredient.Name] >= float64(s.getBonusedMaterialAmount(recipe.Type, i.Amount)) {
+					continue
+				}
+				if slices.ContainsFunc(s.recipes, func(r GameItem) bool { return r.Name == ingredient.Name }) && s.canMake(ingredient) {
+					return s.begin(ingredient)
+				}
+			}
+		}
+	}
+	for _, recipe := range s.recipes {
+		if recipe.Type != itemType || !s.canMake(recipe) {
+			continue
+		}
+		if s.strategy == CraftItems && recipe.Type == Alloy && s.used[recipe.Name] {
+			continue
+		}
+		return s.begin(recipe)
+	}
+	return nil
+}
+
+func (s *simulation) begin(recipe GameItem) *simJob {
+	for _, i := range recipe.Ingredients {
+		s.stock[i.Item.Name] -= float64(s.getBonusedMaterialAmount(recipe.Type, i.Amount))
+	}
+	return &simJob{recipe, s.getDuration(recipe)}
+}
+
+// canMine is true when everything item is made from comes from mined ore
+func (s *simulation) canMine(item GameItem) bool {
+	if item.Type == Ore {
+		return s.config.MiningRates[item.Name] > 0
+	}
+	for _, i := range item.Ingredients {
+		if !s.canMine(s.data[i.Item.Name]) {
+			return false
+		}
+	}
+	return len(item.Ingredients) > 0
+}
+
+func (s *simulation) canMake(recipe GameItem) bool {
+	for _, i := range recipe.Ingredients {
+		if s.stock[i.Item.Name] < float64(s.getBonusedMaterialAmount(recipe.Type, i.Amount)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *simulation) finish(item GameItem) {
+	if s.strategy == CraftItems && s.used[item.Name] {
+		s.stock[item.Name]++
+		return
+	}
+	s.cash += float64(s.getBonusedValue(item.Type, item.Value))
+}
+
+// parseMiningRates reads "Copper 10, Iron=2.5" style ore rates per second,
+// matching the names against the ores in data.
+func parseMiningRates(text string, data map[string]GameItem) (map[string]float64, error) {
+	ores := make([]string, 0)
+	for name, item := range data {
+		if item.Type == Ore {
+			ores = append(ores, name)
+		}
+	}
+	slices.Sort(ores)
+
+	rates := make(map[string]float64)
+	for _, part := range strings.FieldsFunc(text, func(r rune) bool {
+		return r == '\n' || r == ',' || r == ';'
+	}) {
+		part = strings.ReplaceAll(part, "=", " ")
+		fields := strings.Fields(part)
+		if len(fields) < 2 {
+			continue
+		}
+		rate, err := strconv.ParseFloat(fields[len(fields)-1], 64)
+		if err != nil || rate < 0 {
+			return nil, fmt.Errorf("not a mining rate: %s", strings.TrimSpace(part))
+		}
+		name := strings.Join(fields[:len(fields)-1], " ")
+		match, _, found := matchItem(name, ores)
+		if !found {
+			return nil, fmt.Errorf("unknown ore: %s", name)
+		}
+		rates[match] += rate
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("no mining rates given")
+	}
+	return rates, nil
+}
+
+func writeSimCSV(w io.Writer, results []SimResult) error {
+	writer := csv.NewWriter(w)
+	header := []string{"seconds"}
+	for _, r := range results {
+		header = append(header, r.Strategy.String())
+	}
+	writer.Write(header)
+	for index, sample := range results[0].Samples {
+		row := []string{strconv.Itoa(int(sample.Time.Seconds()))}
+		for _, r := range results {
+			row = append(row, strconv.Itoa(r.Samples[index].Cash))
+		}
+		writer.Write(row)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func runSim(data *jsonGameData, args []string) error {
+	flags := flag.NewFlagSet("sim", flag.ExitOnError)
+	ores := flags.String("ore", "Copper=10", "ore mined per second, e.g. \"Copper=10,Iron=5\"")
+	smelters := flags.Int("smelters", 1, "smelter slots")
+	crafters := flags.Int("crafters", 1, "crafter slots")
+	hours := flags.Float64("hours", 8, "hours to simulate")
+	defaultTime := flags.Duration("default-time", defaultSimTime, "smelt / craft time for anything without one in the game data")
+	target := flags.String("target", "", "cash target, e.g. 5M")
+	strategy := flags.String("strategy", "all", "sell, smelt, craft or all")
+	prefsPath := flags.String("prefs", getPreferencesPath(), "preferences file to read bonuses from")
+	flags.Parse(args)
+
+	gameData := getGameData(data)
+	config := SimConfig{
+		Smelters:    *smelters,
+		Crafters:    *crafters,
+		DefaultTime: *defaultTime,
+	}
+	// NaN, infinite and over-long times would wrap around as a Duration
+	if *hours > 0 && *hours*float64(time.Hour) < math.MaxInt64 {
+		config.Duration = time.Duration(*hours * float64(time.Hour))
+	}
+	if err := config.check(); err != nil {
+		flags.Usage()
+		return err
+	}
+	var err error
+	if config.MiningRates, err = parseMiningRates(*ores, gameData); err != nil {
+		return err
+	}
+	if *target != "" {
+		if config.Target, err = parseAmount(*target); err != nil {
+			return err
+		}
+	}
+	strategies := []SimStrategy{SellOre, SmeltBars, CraftItems}
+	if *strategy != "all" {
+		s, found := simStrategyFlag[*strategy]
+		if !found {
+			return fmt.Errorf("unknown strategy: %s", *strategy)
+		}
+		strategies = []SimStrategy{s}
+	}
+
+	prefs, err := openFilePreferences(*prefsPath)
+	if err != nil {
+		return fmt.Errorf("could not read preferences: %w", err)
+	}
+	bonuses := loadBonuses(prefs)
+	results := make([]SimResult, 0)
+	for _, s := range strategies {
+		result := bonuses.simulate(config, s, gameData)
+		results = append(results, result)
+		if config.Target > 0 {
+			fmt.Fprintln(os.Stderr, result.TargetReport())
+		}
+	}
+	return writeSimCSV(os.Stdout, results)
+}
+
+func (r SimResult) TargetReport() string {
+	if !r.TargetReached {
+		return fmt.Sprintf(tr("%s: target not reached"), tr(r.Strategy.String()))
+	}
+	return fmt.Sprintf(tr("%s: target reached after %s"), tr(r.Strategy.String()), r.TargetTime)
+}
+
+func (a *App) simHandler() {
+	ores := widget.NewMultiLineEntry()
+	ores.SetText(a.app.Preferences().StringWithFallback("simOres", "Copper 10\nIron 5"))
+	ores.SetMinRowsVisible(3)
+	getCountEntry := func(val string) *widget.Entry {
+		entry := widget.NewEntry()
+		entry.SetText(val)
+		entry.Validator = validation.NewRegexp("^[0-9]+$", tr("Whole numbers only please"))
+		return entry
+	}
+	smelters := getCountEntry("1")
+	crafters := getCountEntry("1")
+	hours := getCountEntry("8")
+	target := widget.NewEntry()
+	target.SetPlaceHolder("$5M")
+	chart := NewSimChart()
+	report := widget.NewLabel("")
+
+	runButton := widget.NewButtonWithIcon(tr("Run"), theme.MediaPlayIcon(), func() {
+		rates, err := parseMiningRates(ores.Text, a.data)
+		if err != nil {
+			dialog.ShowError(err, a.mainWindow)
+			return
+		}
+		a.app.Preferences().SetString("simOres", ores.Text)
+		config := SimConfig{
+			MiningRates: rates,
+			DefaultTime: defaultSimTime,
+		}
+		config.Smelters, _ = strconv.Atoi(smelters.Text)
+		config.Crafters, _ = strconv.Atoi(crafters.Text)
+		duration, _ := strconv.Atoi(hours.Text)
+		config.Duration = time.Duration(max(1, duration)) * time.Hour
+		if target.Text != "" {
+			if config.Target, err = parseAmount(target.Text); err != nil {
+				dialog.ShowError(err, a.mainWindow)
+				return
+			}
+		}
+
+		results := make([]SimResult, 0)
+		lines := make([]string, 0)
+		for _, strategy := range []SimStrategy{SellOre, SmeltBars, CraftItems} {
+			result := a.simulate(config, strategy, a.data)
+			results = append(results, result)
+			if config.Target > 0 {
+				lines = append(lines, result.TargetReport())
+			}
+		}
+		chart.Display(results)
+		report.SetText(strings.Join(lines, "\n"))
+	})
+
+	simDialog := dialog.NewCustom(tr("Simulate"), tr("Close"), container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem(tr("Ore / sec"), ores),
+			widget.NewFormItem(tr("Smelters"), smelters),
+			widget.NewFormItem(tr("Crafters"), crafters),
+			widget.NewFormItem(tr("Hours"), hours),
+			widget.NewFormItem(tr("Target"), target),
+		),
+		runButton,
+		chart,
+		report,
+	), a.mainWindow)
+	simDialog.Resize(simDialog.MinSize().AddWidthHeight(100, 0))
+	simDialog.Show()
+}
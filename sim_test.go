@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSimConfigCheck(t *testing.T) {
+	data := getGameData(loadData())
+	for _, config := range []SimConfig{
+		{Smelters: -1, Crafters: 1, Duration: time.Hour},
+		{Smelters: 1, Crafters: -1, Duration: time.Hour},
+		{Smelters: 1, Crafters: 1, Duration: -time.Hour},
+	} {
+		if config.check() == nil {
+			t.Errorf("no error for %+v", config)
+		}
+		config.MiningRates = map[string]float64{"Copper": 10}
+		NewBonuses().simulate(config, CraftItems, data)
+	}
+	if err := (SimConfig{Duration: time.Hour}).check(); err != nil {
+		t.Errorf("error for no slots: %s", err)
+	}
+}
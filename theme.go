@@ -0,0 +1,109 @@
+package main
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+type ThemeVariant int
+
+const (
+	SystemVariant ThemeVariant = iota
+	LightVariant
+	DarkVariant
+)
+
+var themeVariantName = map[ThemeVariant]string{
+	SystemVariant: "System",
+	LightVariant:  "Light",
+	DarkVariant:   "Dark",
+}
+
+func (v ThemeVariant) String() string {
+	return themeVariantName[v]
+}
+
+const (
+	ColorNameOre   fyne.ThemeColorName = "ore"
+	ColorNameAlloy fyne.ThemeColorName = "alloy"
+	ColorNameItem  fyne.ThemeColorName = "item"
+)
+
+var itemTypeColorName = map[ItemType]fyne.ThemeColorName{
+	Ore:   ColorNameOre,
+	Alloy: ColorNameAlloy,
+	Item:  ColorNameItem,
+}
+
+// itemTypeColors are the ore, alloy and item colours for the dark and light
+// variants, darker on the light background so they keep their contrast.
+var itemTypeColors = map[fyne.ThemeVariant]map[fyne.ThemeColorName]color.Color{
+	theme.VariantDark: {
+		ColorNameOre:   color.NRGBA{0xd8, 0x9a, 0x5b, 0xff},
+		ColorNameAlloy: color.NRGBA{0x8f, 0xb4, 0xd6, 0xff},
+		ColorNameItem:  color.NRGBA{0x9c, 0xcc, 0x7a, 0xff},
+	},
+	theme.VariantLight: {
+		ColorNameOre:   color.NRGBA{0x9a, 0x5a, 0x1e, 0xff},
+		ColorNameAlloy: color.NRGBA{0x3c, 0x6a, 0x96, 0xff},
+		ColorNameItem:  color.NRGBA{0x4a, 0x7d, 0x2a, 0xff},
+	},
+}
+
+// appTheme is the default theme with a fixed light or dark variant if one
+// was picked, an accent colour in place of the system primary colour and
+// colours for each item type.
+type appTheme struct {
+	variant ThemeVariant
+	accent  string // one of theme.PrimaryColorNames, empty for the system's
+}
+
+func withAlpha(c color.Color, alpha uint8) color.Color {
+	r, g, b, _ := c.RGBA()
+	return color.NRGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), alpha}
+}
+
+func (t *appTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	switch t.variant {
+	case LightVariant:
+		variant = theme.VariantLight
+	case DarkVariant:
+		variant = theme.VariantDark
+	}
+	if c, found := itemTypeColors[variant][name]; found {
+		return c
+	}
+	if t.accent != "" {
+		accent := theme.PrimaryColorNamed(t.accent)
+		switch name {
+		case theme.ColorNamePrimary, theme.ColorNameHyperlink:
+			return accent
+		case theme.ColorNameFocus:
+			return withAlpha(accent, 0x7f)
+		case theme.ColorNameSelection:
+			return withAlpha(accent, 0x3f)
+		}
+	}
+	return theme.DefaultTheme().Color(name, variant)
+}
+
+func (t *appTheme) Font(style fyne.TextStyle) fyne.Resource {
+	return theme.DefaultTheme().Font(style)
+}
+
+func (t *appTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
+	return theme.DefaultTheme().Icon(name)
+}
+
+func (t *appTheme) Size(name fyne.ThemeSizeName) float32 {
+	return theme.DefaultTheme().Size(name)
+}
+
+func (a *App) applyTheme() {
+	a.app.Settings().SetTheme(&appTheme{
+		variant: ThemeVariant(a.app.Preferences().IntWithFallback("themeVariant", int(SystemVariant))),
+		accent:  a.app.Preferences().String("accentColor"),
+	})
+}
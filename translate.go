@@ -0,0 +1,96 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"fyne.io/fyne/v2/lang"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+//go:embed translations
+var translationFiles embed.FS
+
+// languages with UI translations, the first is used when nothing matches
+var languages = []language.Tag{language.English, language.German, language.Portuguese}
+
+var languageNames = map[string]string{
+	"en": "English",
+	"de": "Deutsch",
+	"pt": "Português",
+}
+
+var (
+	currentLanguage = "en"
+	translator      *i18n.Localizer
+	itemNames       = make(map[string]string)
+)
+
+func loadTranslations() (*i18n.Bundle, error) {
+	bundle := i18n.NewBundle(language.English)
+	bundle.RegisterUnmarshalFunc("json", json.Unmarshal)
+	files, err := translationFiles.ReadDir("translations")
+	if err != nil {
+		return bundle, err
+	}
+	for _, f := range files {
+		if _, err := bundle.LoadMessageFileFS(translationFiles, path.Join("translations", f.Name())); err != nil {
+			return bundle, err
+		}
+	}
+	return bundle, nil
+}
+
+// setLanguage switches the UI text to the closest translation to code, an
+// empty code follows the system locale.
+func setLanguage(code string) {
+	if code == "" {
+		code = lang.SystemLocale().String()
+	}
+	_, index, _ := language.NewMatcher(languages).Match(language.Make(code))
+	currentLanguage = languages[index].String()
+
+	bundle, err := loadTranslations()
+	if err != nil {
+		fmt.Printf("could not load translations: %s\n", err)
+	}
+	translator = i18n.NewLocalizer(bundle, currentLanguage)
+}
+
+// tr translates UI text, the English text is the key and is shown when
+// there's no translation.
+func tr(text string) string {
+	if translator == nil {
+		return text
+	}
+	translated, err := translator.Localize(&i18n.LocalizeConfig{MessageID: text})
+	if err != nil {
+		return text
+	}
+	return translated
+}
+
+// setItemNames picks the item names for the current language out of the
+// game data, items without one keep their English name.
+func setItemNames(data *jsonGameData) {
+	itemNames = make(map[string]string)
+	for _, itemType := range itemTypes {
+		for _, item := range *data.section(itemType) {
+			if name := item.Names[currentLanguage]; name != "" {
+				itemNames[item.Name] = name
+			}
+		}
+	}
+}
+
+// itemName is the name to show for an item, names are always stored and
+// looked up in English.
+func itemName(name string) string {
+	if translated, found := itemNames[name]; found {
+		return translated
+	}
+	return name
+}
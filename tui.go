@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+const maxPickerOptions = 9
+
+type TUI struct {
+	data     map[string]GameItem
+	itemList []string
+	orders   []Ingredient
+	prefs    *filePreferences
+	input    *bufio.Scanner
+	output   io.Writer
+	message  string
+	Bonuses
+}
+
+func runTUI(data *jsonGameData, args []string) error {
+	flags := flag.NewFlagSet("tui", flag.ExitOnError)
+	prefsPath := flags.String("prefs", getPreferencesPath(), "preferences file shared with the desktop app")
+	flags.Parse(args)
+
+	prefs, err := openFilePreferences(*prefsPath)
+	if err != nil {
+		return fmt.Errorf("could not read preferences: %w", err)
+	}
+	return NewTUI(data, prefs, os.Stdin, os.Stdout).Run()
+}
+
+func NewTUI(data *jsonGameData, prefs *filePreferences, input io.Reader, output io.Writer) *TUI {
+	gameData := getGameData(data)
+	return &TUI{
+		data:     gameData,
+		itemList: getItemList(gameData),
+		orders:   make([]Ingredient, 0),
+		prefs:    prefs,
+		input:    bufio.NewScanner(input),
+		output:   output,
+		Bonuses:  loadBonuses(prefs),
+	}
+}
+
+func (t *TUI) Run() error {
+	for {
+		t.draw()
+		fmt.Fprint(t.output, "> ")
+		if !t.input.Scan() {
+			return t.input.Err()
+		}
+		command, args, _ := strings.Cut(strings.TrimSpace(t.input.Text()), " ")
+		args = strings.TrimSpace(args)
+		t.message = ""
+
+		switch strings.ToLower(command) {
+		case "a", "add":
+			t.addCommand(args)
+		case "r", "rm", "remove":
+			t.removeCommand(args)
+		case "s", "set":
+			t.setCommand(args)
+		case "b", "bonus":
+			t.bonusCommand(args)
+		case "c", "clear":
+			t.orders = t.orders[:0]
+		case "h", "help", "":
+			t.message = "add <item> [amount], rm <n>, set <n> <amount>, bonus <name> <value>, clear, quit"
+		case "q", "quit", "exit":
+			return nil
+		default:
+			t.message = fmt.Sprintf("unknown command: %s, try help", command)
+		}
+	}
+}
+
+// pickItem fuzzy matches query against the orderable items, asking the user
+// to choose when there's no single good match.
+func (t *TUI) pickItem(query string) (string, bool) {
+	matches := rankItems(query, t.itemList)
+	if len(matches) == 0 {
+		t.message = fmt.Sprintf("no item matches \"%s\"", query)
+		return "", false
+	}
+	if score, _ := matchScore(query, matches[0]); score == matchExact || len(matches) == 1 {
+		return matches[0], true
+	}
+
+	matches = matches[:min(len(matches), maxPickerOptions)]
+	for index, name := range matches {
+		fmt.Fprintf(t.output, "  %d. %s\n", index+1, name)
+	}
+	fmt.Fprint(t.output, "pick> ")
+	if !t.input.Scan() {
+		return "", false
+	}
+	choice, err := strconv.Atoi(strings.TrimSpace(t.input.Text()))
+	if err != nil || choice < 1 || choice > len(matches) {
+		t.message = "nothing added"
+		return "", false
+	}
+	return matches[choice-1], true
+}
+
+func (t *TUI) addCommand(args string) {
+	query, amount := parseOrderLine(args)
+	if query == "" {
+		t.message = "usage: add <item> [amount]"
+		return
+	}
+	name, ok := t.pickItem(query)
+	if !ok {
+		return
+	}
+	t.orders = append(t.orders, Ingredient{
+		Item:   t.data[name],
+		Amount: amount,
+	})
+}
+
+func (t *TUI) getOrderIndex(arg string) (int, bool) {
+	index, err := strconv.Atoi(arg)
+	if err != nil || index < 1 || index > len(t.orders) {
+		t.message = fmt.Sprintf("no order number %s", arg)
+		return 0, false
+	}
+	return index - 1, true
+}
+
+func (t *TUI) removeCommand(args string) {
+	if index, ok := t.getOrderIndex(args); ok {
+		t.orders = append(t.orders[:index], t.orders[index+1:]...)
+	}
+}
+
+func (t *TUI) setCommand(args string) {
+	fields := strings.Fields(args)
+	if len(fields) < 2 {
+		t.message = "usage: set <n> <amount>"
+		return
+	}
+	index, ok := t.getOrderIndex(fields[0])
+	if !ok {
+		return
+	}
+	amount, err := parseAmount(strings.Join(fields[1:], " "))
+	if err != nil || amount < 1 {
+		t.message = "amounts like 12, 1.5K or 2e6 please"
+		return
+	}
+	t.orders[index].Amount = amount
+}
+
+func (t *TUI) bonusCommand(args string) {
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		t.message = "usage: bonus <craft-eff|smelt-eff|smelt|craft|underforge|dorms> <value>"
+		return
+	}
+	enabled := fields[1] == "on" || fields[1] == "true" || fields[1] == "yes"
+	switch strings.ToLower(fields[0]) {
+	case "craft-eff":
+		t.CraftingEfficiency = enabled
+	case "smelt-eff":
+		t.SmeltingEfficiency = enabled
+	case "smelt":
+		t.SmeltValBonus = parseBonus(fields[1])
+	case "craft":
+		t.CraftValBonus = parseBonus(fields[1])
+	case "underforge":
+		t.UnderforgeBonus = parseBonus(fields[1])
+	case "dorms":
+		t.DormsBonus = parseBonus(fields[1])
+	default:
+		t.message = fmt.Sprintf("unknown bonus: %s", fields[0])
+		return
+	}
+	saveBonuses(t.prefs, t.Bonuses)
+	if err := t.prefs.Save(); err != nil {
+		t.message = fmt.Sprintf("could not save bonuses: %s", err)
+	}
+}
+
+func (t *TUI) draw() {
+	out := t.output
+	money := func(val int) string {
+		return fmt.Sprintf("$%s", humanize.Comma(int64(val)))
+	}
+	check := func(val bool) string {
+		if val {
+			return "x"
+		}
+		return " "
+	}
+
+	fmt.Fprint(out, "\033[H\033[2J")
+	fmt.Fprintln(out, "Idle Planet Calc")
+	fmt.Fprintln(out, "\nOrders")
+	for index, o := range t.orders {
+		fmt.Fprintf(out, "  %d. %s x %s\n", index+1, humanize.Comma(int64(o.Amount)), o.Item.Name)
+	}
+	fmt.Fprintf(out, "\nBonuses  craft-eff [%s]  smelt-eff [%s]  smelt %s  craft %s  underforge %s  dorms %s\n",
+		check(t.CraftingEfficiency), check(t.SmeltingEfficiency),
+		formatBonus(t.SmeltValBonus), formatBonus(t.CraftValBonus),
+		formatBonus(t.UnderforgeBonus), formatBonus(t.DormsBonus))
+
+	results, summary := t.calculate(t.orders)
+	if len(results) > 0 {
+		fmt.Fprintf(out, "\n%-24s %16s %24s\n", "Item", "Amount", "Value")
+		for _, r := range results {
+			fmt.Fprintf(out, "%-24s %16s %24s\n", r.Item.Name, humanize.Comma(int64(r.Amount)), money(r.Value))
+		}
+
+		fmt.Fprintln(out, "\nSummary")
+		total := 0
+		for _, o := range summary {
+			total += o.Value
+			fmt.Fprintf(out, "  %s x %s  %s\n", humanize.Comma(int64(o.Amount)), o.Name, money(o.Value))
+			for _, i := range o.Ingredients {
+				fmt.Fprintf(out, "      %s x %s\n", humanize.Comma(int64(i.Amount)), i.Name)
+			}
+		}
+		fmt.Fprintf(out, "  Total: %s\n", money(total))
+	}
+
+	if t.message != "" {
+		fmt.Fprintf(out, "\n%s\n", t.message)
+	}
+	fmt.Fprintln(out)
+}
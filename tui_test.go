@@ -0,0 +1,26 @@
+package main
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetCommand(t *testing.T) {
+	prefs, err := openFilePreferences(filepath.Join(t.TempDir(), "preferences.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tui := NewTUI(loadData(), prefs, strings.NewReader(""), io.Discard)
+	tui.addCommand("Robot")
+	for _, test := range []struct {
+		args   string
+		amount int
+	}{{"1 2e6", 2e6}, {"1 1.5 K", 1500}, {"1 lots", 1500}, {"1 0", 1500}} {
+		tui.setCommand(test.args)
+		if amount := tui.orders[0].Amount; amount != test.amount {
+			t.Errorf("set %s: amount %d, want %d", test.args, amount, test.amount)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+"use strict";
+
+const orders = document.getElementById("orders");
+const bonuses = document.getElementById("bonuses");
+const errorText = document.getElementById("error");
+let itemNames = [];
+
+const comma = (value) => value.toLocaleString("en-US");
+
+function addOrder(name = "", amount = 1) {
+  const row = document.getElementById("order-row").content.firstElementChild.cloneNode(true);
+  const select = row.querySelector("select");
+  select.append(new Option("(Select one)", ""));
+  for (const item of itemNames) {
+    select.append(new Option(item, item));
+  }
+  select.value = name;
+  row.querySelector("input").value = amount;
+  row.querySelector(".remove").addEventListener("click", () => {
+    row.remove();
+    saveState();
+  });
+  row.addEventListener("change", saveState);
+  orders.append(row);
+}
+
+function getOrders() {
+  return [...orders.querySelectorAll(".order")]
+    .map((row) => ({
+      name: row.querySelector("select").value,
+      amount: parseInt(row.querySelector("input").value, 10) || 1,
+    }))
+    .filter((order) => order.name !== "");
+}
+
+function getBonuses() {
+  const result = {};
+  for (const input of bonuses.elements) {
+    if (input.type === "checkbox") {
+      result[input.name] = input.checked;
+    } else {
+      result[input.name] = parseFloat(input.value) || 1;
+    }
+  }
+  return result;
+}
+
+function saveState() {
+  localStorage.setItem("orders", JSON.stringify(getOrders()));
+  localStorage.setItem("bonuses", JSON.stringify(getBonuses()));
+}
+
+function loadState() {
+  const savedBonuses = JSON.parse(localStorage.getItem("bonuses") || "{}");
+  for (const input of bonuses.elements) {
+    if (!(input.name in savedBonuses)) {
+      continue;
+    }
+    if (input.type === "checkbox") {
+      input.checked = savedBonuses[input.name];
+    } else {
+      input.value = savedBonuses[input.name].toFixed(2);
+    }
+  }
+  for (const order of JSON.parse(localStorage.getItem("orders") || "[]")) {
+    addOrder(order.name, order.amount);
+  }
+}
+
+function displayResults(data) {
+  const body = document.querySelector("#results tbody");
+  body.replaceChildren(...data.results.map((row) => {
+    const tr = document.createElement("tr");
+    for (const text of [row.name, comma(row.amount), "$" + comma(row.value)]) {
+      tr.append(Object.assign(document.createElement("td"), { textContent: text }));
+    }
+    return tr;
+  }));
+
+  const summary = document.getElementById("summary");
+  summary.replaceChildren();
+  let total = 0;
+  let group = "";
+  for (const order of data.orders) {
+    total += order.value;
+    if ((order.group || "") !== group) {
+      group = order.group || "";
+      summary.append(Object.assign(document.createElement("div"), {
+        className: "group",
+        textContent: group || "Ungrouped",
+      }));
+    }
+    const div = Object.assign(document.createElement("div"), { className: "summary-order" });
+    div.append(`${comma(order.amount)} x ${order.name} ($${comma(order.value)})`);
+    for (const ingredient of order.ingredients || []) {
+      div.append(Object.assign(document.createElement("small"), {
+        textContent: `${comma(ingredient.amount)} x ${ingredient.name}`,
+      }));
+    }
+    summary.append(div);
+  }
+  summary.append(Object.assign(document.createElement("div"), {
+    className: "group",
+    textContent: "Total: $" + comma(total),
+  }));
+}
+
+async function calculate() {
+  saveState();
+  errorText.textContent = "";
+  const response = await fetch("api/calculate", {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ orders: getOrders(), bonuses: getBonuses() }),
+  });
+  const data = await response.json();
+  if (!response.ok) {
+    errorText.textContent = data.error;
+    return;
+  }
+  displayResults(data);
+}
+
+async function init() {
+  const response = await fetch("api/items");
+  const items = await response.json();
+  itemNames = items
+    .filter((item) => item.type !== "Ore")
+    .reverse()
+    .map((item) => item.name);
+
+  document.getElementById("add").addEventListener("click", () => addOrder());
+  document.getElementById("calculate").addEventListener("click", calculate);
+  bonuses.addEventListener("change", saveState);
+  loadState();
+}
+
+init();
@@ -1,341 +1,841 @@
-package main
-
-import (
-	"fmt"
-	"strconv"
-
-	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/container"
-	"fyne.io/fyne/v2/data/validation"
-	"fyne.io/fyne/v2/theme"
-	"fyne.io/fyne/v2/widget"
-	"github.com/dustin/go-humanize"
-)
-
-type Order struct {
-	widget.BaseWidget
-	options       []string
-	orderItem     GameItem
-	amount        int
-	onRemoved     func()
-	onItemChanged func(string)
-}
-
-func NewOrder(options []string) *Order {
-	item := &Order{
-		options: options,
-	}
-	item.ExtendBaseWidget(item)
-	return item
-}
-
-func (o *Order) SetOnItemChanged(onItemChanged func(string)) {
-	o.onItemChanged = onItemChanged
-}
-
-func (o *Order) SetOnRemoved(onRemoved func()) {
-	o.onRemoved = onRemoved
-}
-
-func (o *Order) CreateRenderer() fyne.WidgetRenderer {
-	amount := widget.NewEntry()
-	amount.SetText("1")
-	o.amount = 1
-	amount.Validator = validation.NewRegexp("^[0-9]+$", "Whole numbers only please")
-	amount.OnChanged = func(s string) {
-		val, err := strconv.ParseInt(s, 10, 0)
-		if err != nil || val == int64(0) {
-			o.amount = 1
-		}
-		o.amount = int(val)
-	}
-	amount.Resize(amount.MinSize().AddWidthHeight(11, 0))
-
-	decrement := widget.NewButtonWithIcon("", theme.MediaFastRewindIcon(), func() {
-		o.amount -= 1
-		amount.SetText(strconv.Itoa(o.amount))
-	})
-	increment := widget.NewButtonWithIcon("", theme.MediaFastForwardIcon(), func() {
-		o.amount += 1
-		amount.SetText(strconv.Itoa(o.amount))
-	})
-	remove := widget.NewButtonWithIcon("", theme.ContentRemoveIcon(), func() {
-		o.onRemoved()
-	})
-
-	decrement.Resize(decrement.MinSize().AddWidthHeight(-10, -10))
-	increment.Resize(increment.MinSize().AddWidthHeight(-10, -10))
-	remove.Resize(remove.MinSize().AddWidthHeight(-10, -10))
-
-	itemSelector := widget.NewSelect(o.options, func(input string) {
-		o.onItemChanged(input)
-	})
-	itemSelector.Resize(itemSelector.MinSize().AddWidthHeight(20, 0))
-
-	return &orderRenderer{
-		order:        o,
-		itemSelector: itemSelector,
-		amount:       amount,
-		increment:    increment,
-		decrement:    decrement,
-		remove:       remove,
-	}
-}
-
-type orderRenderer struct {
-	order                        *Order
-	itemSelector                 *widget.Select
-	amount                       *widget.Entry
-	increment, decrement, remove *widget.Button
-}
-
-func (o *orderRenderer) Destroy() {
-}
-
-func (o *orderRenderer) Layout(size fyne.Size) {
-	padding := float32(4)
-	pos := fyne.NewPos(padding, padding)
-	buttonHeight := (size.Height / 2) - (o.increment.Size().Height / 2)
-	o.itemSelector.Move(pos)
-	pos.X += o.itemSelector.Size().Width + (padding * 2)
-	pos.Y = buttonHeight
-	o.decrement.Move(pos)
-	pos.X += o.decrement.Size().Width + (padding * 2)
-	pos.Y = padding
-	o.amount.Move(pos)
-	pos.X += o.amount.Size().Width + (padding * 2)
-	pos.Y = buttonHeight
-	o.increment.Move(pos)
-	pos.X += o.increment.Size().Width + (padding * 2)
-	pos.X = size.Width - (padding + o.remove.MinSize().Width)
-	o.remove.Move(pos)
-}
-
-func (o *orderRenderer) MinSize() fyne.Size {
-	width := o.itemSelector.MinSize().Width +
-		o.amount.Size().Width + 8
-	height := o.itemSelector.MinSize().Height + 8
-	return fyne.NewSize(width, height)
-}
-
-func (o *orderRenderer) Objects() []fyne.CanvasObject {
-	return []fyne.CanvasObject{o.itemSelector, o.amount, o.increment, o.decrement, o.remove}
-}
-
-func (o *orderRenderer) Refresh() {
-	o.itemSelector.Refresh()
-	o.amount.Refresh()
-}
-
-type SummaryScreen struct {
-	widget.BaseWidget
-	ingredients []*ResultSummary
-	renderer    *summaryScreenRenderer
-}
-
-func NewSummaryScreen() *SummaryScreen {
-	item := &SummaryScreen{
-		ingredients: make([]*ResultSummary, 0),
-	}
-	item.ExtendBaseWidget(item)
-	return item
-}
-
-func (s *SummaryScreen) Display(ingredients []ResultItem) {
-	s.ingredients = make([]*ResultSummary, 0)
-	for _, i := range ingredients {
-		s.ingredients = append(s.ingredients, NewResultSummary(i))
-	}
-	s.renderer.container = container.NewVBox()
-}
-
-func (s *SummaryScreen) CreateRenderer() fyne.WidgetRenderer {
-	item := &summaryScreenRenderer{
-		summaryScreen: s,
-		container:     container.NewVBox(),
-	}
-	s.renderer = item
-	return item
-}
-
-type summaryScreenRenderer struct {
-	summaryScreen *SummaryScreen
-	container     *fyne.Container
-}
-
-func (s *summaryScreenRenderer) Destroy() {
-}
-
-func (s *summaryScreenRenderer) Layout(size fyne.Size) {
-	s.container.Resize(size)
-	s.CheckChildren()
-	s.CheckLabels()
-}
-
-func (s *summaryScreenRenderer) CheckLabels() {
-	minWidth := float32(0)
-	for _, o := range s.container.Objects {
-		if item, ok := o.(*ResultSummary); ok {
-			width := item.renderer.itemLabel.MinSize().Width
-			if item.renderer.valueLabel.MinSize().Width > width {
-				width = item.renderer.valueLabel.MinSize().Width
-			}
-			if minWidth < width {
-				minWidth = width
-			}
-		}
-	}
-
-	for _, o := range s.container.Objects {
-		if item, ok := o.(*ResultSummary); ok {
-			item.setLabelWidth(minWidth)
-		}
-	}
-}
-
-func (s *summaryScreenRenderer) MinSize() fyne.Size {
-	return s.container.MinSize()
-}
-
-func (s *summaryScreenRenderer) Objects() []fyne.CanvasObject {
-	return []fyne.CanvasObject{s.container}
-}
-
-func (s *summaryScreenRenderer) CheckChildren() {
-	if len(s.container.Objects) == 0 &&
-		len(s.summaryScreen.ingredients) > 0 {
-		total := 0
-		for index, ingredient := range s.summaryScreen.ingredients {
-			total += ingredient.result.Value
-			if index > 0 {
-				s.container.Add(getSeparator())
-			}
-			s.container.Add(ingredient)
-		}
-		s.container.Add(getSeparator())
-		s.container.Add(widget.NewLabel(
-			fmt.Sprintf("Total: $%s", humanize.Comma(int64(total)))))
-	}
-}
-
-func (s *summaryScreenRenderer) Refresh() {
-	s.CheckChildren()
-	s.CheckLabels()
-	s.container.Refresh()
-}
-
-type ResultSummary struct {
-	widget.BaseWidget
-	labelWidth float32
-	result     ResultItem
-	renderer   *resultSummaryRenderer
-}
-
-func NewResultSummary(result ResultItem) *ResultSummary {
-	item := &ResultSummary{
-		result: result,
-	}
-	item.ExtendBaseWidget(item)
-	return item
-}
-
-func (r *ResultSummary) setLabelWidth(value float32) {
-	r.labelWidth = value
-}
-
-func (r *ResultSummary) CreateRenderer() fyne.WidgetRenderer {
-	nameLabel := widget.NewLabel(fmt.Sprintf("%d x %s",
-		r.result.Amount, r.result.Name))
-	valueLabel := widget.NewLabel(fmt.Sprintf("$%s",
-		humanize.Comma(int64(r.result.Value))))
-	valueLabel.SizeName = theme.SizeNameCaptionText
-
-	subContainer := container.NewVBox()
-
-	renderer := &resultSummaryRenderer{
-		resultSummary: r,
-		itemLabel:     nameLabel,
-		valueLabel:    valueLabel,
-		subContainer:  subContainer,
-	}
-
-	r.renderer = renderer
-	return renderer
-}
-
-type resultSummaryRenderer struct {
-	resultSummary *ResultSummary
-	itemLabel     *widget.Label
-	valueLabel    *widget.Label
-	subContainer  *fyne.Container
-}
-
-func (r *resultSummaryRenderer) Destroy() {
-}
-
-func (r *resultSummaryRenderer) Layout(fyne.Size) {
-	r.CheckChildren()
-	padding := float32(2)
-	pos := fyne.NewPos(padding, padding)
-
-	r.itemLabel.Move(pos)
-	r.itemLabel.Resize(fyne.NewSize(r.resultSummary.labelWidth, r.itemLabel.MinSize().Height))
-
-	widest := r.itemLabel.Size().Width
-	if r.valueLabel.Size().Width > widest {
-		widest = r.valueLabel.Size().Width
-	}
-
-	pos.X = widest + (padding * 4)
-	r.subContainer.Resize(r.subContainer.MinSize())
-	r.subContainer.Move(pos)
-
-	pos.X = padding
-	pos.Y = r.itemLabel.Size().Height + padding
-	r.valueLabel.Resize(r.valueLabel.MinSize())
-	r.valueLabel.Move(pos)
-}
-
-func (r *resultSummaryRenderer) MinSize() fyne.Size {
-	size := r.itemLabel.MinSize()
-
-	if r.valueLabel.MinSize().Width > size.Width {
-		size.Width = r.valueLabel.MinSize().Width
-	}
-
-	if r.resultSummary.labelWidth > size.Width {
-		size.Width = float32(r.resultSummary.labelWidth)
-	}
-
-	size.Width += r.subContainer.MinSize().Width
-	size.Height += r.valueLabel.MinSize().Height
-
-	if r.subContainer.MinSize().Height > size.Height {
-		size.Height = r.subContainer.MinSize().Height
-	}
-
-	return size
-}
-
-func (r *resultSummaryRenderer) Objects() []fyne.CanvasObject {
-	return []fyne.CanvasObject{r.itemLabel, r.valueLabel, r.subContainer}
-}
-
-func (r *resultSummaryRenderer) CheckChildren() {
-	if len(r.subContainer.Objects) == 0 &&
-		len(r.resultSummary.result.Ingredients) > 0 {
-		for index, ingredient := range r.resultSummary.result.Ingredients {
-			if index > 0 {
-				r.subContainer.Add(getSeparator())
-			}
-			label := widget.NewLabel(fmt.Sprintf("%d x %s", ingredient.Amount, ingredient.Name))
-			label.SizeName = theme.SizeNameCaptionText
-			r.subContainer.Add(label)
-		}
-	}
-}
-
-func (r *resultSummaryRenderer) Refresh() {
-	r.CheckChildren()
-	r.subContainer.Refresh()
-}
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+type Order struct {
+	widget.BaseWidget
+	options         []string
+	orderItem       GameItem
+	amount          int
+	onRemoved       func()
+	onItemChanged   func(string)
+	onAmountChanged func(int)
+	onMoved         func(int)
+	onDuplicated    func()
+	onGroupSelected func()
+	group           string
+	heading         string
+	icons           *IconSet
+	renderer        *orderRenderer
+}
+
+func NewOrder(options []string, icons *IconSet) *Order {
+	item := &Order{
+		options: options,
+		amount:  1,
+		icons:   icons,
+	}
+	item.ExtendBaseWidget(item)
+	return item
+}
+
+func (o *Order) SetOnItemChanged(onItemChanged func(string)) {
+	o.onItemChanged = onItemChanged
+}
+
+func (o *Order) SetOptions(options []string) {
+	o.options = options
+	if o.renderer != nil {
+		o.renderer.itemSelector.SetOptions(o.displayOptions())
+	}
+}
+
+// displayOptions are the options by the names shown for the current language
+func (o *Order) displayOptions() []string {
+	names := make([]string, 0)
+	for _, option := range o.options {
+		names = append(names, itemName(option))
+	}
+	return names
+}
+
+// SetItem changes the selected item without calling onItemChanged
+func (o *Order) SetItem(item GameItem) {
+	o.orderItem = item
+	if o.renderer != nil {
+		o.renderer.itemSelector.Selected = itemName(item.Name)
+		o.renderer.itemSelector.Refresh()
+		o.renderer.icon.SetResource(o.icons.Icon(item.Name))
+	}
+}
+
+// SetAmount changes the amount, calling onAmountChanged once the widget has rendered
+func (o *Order) SetAmount(amount int) {
+	o.amount = amount
+	if o.renderer != nil {
+		o.renderer.amount.SetText(strconv.Itoa(amount))
+	}
+}
+
+func (o *Order) SetOnAmountChanged(onAmountChanged func(int)) {
+	o.onAmountChanged = onAmountChanged
+}
+
+func (o *Order) SetOnRemoved(onRemoved func()) {
+	o.onRemoved = onRemoved
+}
+
+// SetOnMoved is called with the number of rows to move by, negative is up
+func (o *Order) SetOnMoved(onMoved func(int)) {
+	o.onMoved = onMoved
+}
+
+func (o *Order) SetOnDuplicated(onDuplicated func()) {
+	o.onDuplicated = onDuplicated
+}
+
+func (o *Order) SetOnGroupSelected(onGroupSelected func()) {
+	o.onGroupSelected = onGroupSelected
+}
+
+// hasFocus reports whether the order's item or amount is focused
+func (o *Order) hasFocus(focused fyne.Focusable) bool {
+	if o.renderer == nil || focused == nil {
+		return false
+	}
+	return focused == fyne.Focusable(o.renderer.itemSelector) || focused == fyne.Focusable(o.renderer.amount)
+}
+
+// SetHeading shows a group heading above the order, empty hides it
+func (o *Order) SetHeading(heading string) {
+	o.heading = heading
+	o.Refresh()
+}
+
+func (o *Order) CreateRenderer() fyne.WidgetRenderer {
+	amount := newShortcutEntry()
+	amount.SetText(strconv.Itoa(o.amount))
+	amount.Validator = func(s string) error {
+		if val, err := parseAmount(s); err != nil || val < 1 {
+			return errors.New(tr("Amounts like 12, 1.5K or 2e6 please"))
+		}
+		return nil
+	}
+	amount.OnChanged = func(s string) {
+		val, err := parseAmount(s)
+		if err != nil || val < 1 {
+			val = 1
+		}
+		o.amount = val
+		if o.onAmountChanged != nil {
+			o.onAmountChanged(o.amount)
+		}
+	}
+	amount.Resize(amount.MinSize().AddWidthHeight(11, 0))
+
+	decrement := widget.NewButtonWithIcon("", theme.MediaFastRewindIcon(), func() {
+		o.amount -= 1
+		amount.SetText(strconv.Itoa(o.amount))
+	})
+	increment := widget.NewButtonWithIcon("", theme.MediaFastForwardIcon(), func() {
+		o.amount += 1
+		amount.SetText(strconv.Itoa(o.amount))
+	})
+	remove := widget.NewButtonWithIcon("", theme.ContentRemoveIcon(), func() {
+		o.onRemoved()
+	})
+
+	decrement.Resize(decrement.MinSize().AddWidthHeight(-10, -10))
+	increment.Resize(increment.MinSize().AddWidthHeight(-10, -10))
+	remove.Resize(remove.MinSize().AddWidthHeight(-10, -10))
+
+	heading := widget.NewLabelWithStyle(o.heading, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	if o.heading == "" {
+		heading.Hide()
+	}
+
+	var dragOffset float32
+	handle := newDragHandle(func(event *fyne.DragEvent) {
+		dragOffset += event.Dragged.DY
+		o.Move(o.Position().AddXY(0, event.Dragged.DY))
+	}, func() {
+		rows := int(math.Round(float64(dragOffset / o.Size().Height)))
+		dragOffset = 0
+		o.onMoved(rows)
+	})
+	handle.Resize(fyne.NewSquareSize(theme.IconInlineSize()))
+
+	var menuButton *widget.Button
+	menuButton = widget.NewButtonWithIcon("", theme.MoreHorizontalIcon(), func() {
+		menu := fyne.NewMenu("",
+			fyne.NewMenuItem(tr("Move up"), func() { o.onMoved(-1) }),
+			fyne.NewMenuItem(tr("Move down"), func() { o.onMoved(1) }),
+			fyne.NewMenuItem(tr("Duplicate"), o.onDuplicated),
+			fyne.NewMenuItem(tr("Group..."), o.onGroupSelected),
+		)
+		canvas := fyne.CurrentApp().Driver().CanvasForObject(menuButton)
+		pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(menuButton)
+		widget.ShowPopUpMenuAtPosition(menu, canvas, pos.AddXY(0, menuButton.Size().Height))
+	})
+	menuButton.Resize(menuButton.MinSize().AddWidthHeight(-10, -10))
+
+	icon := widget.NewIcon(o.icons.Icon(o.orderItem.Name))
+	icon.Resize(fyne.NewSquareSize(theme.IconInlineSize()))
+	var itemSelector *widget.Select
+	itemSelector = widget.NewSelect(o.displayOptions(), func(string) {
+		name := o.options[itemSelector.SelectedIndex()]
+		icon.SetResource(o.icons.Icon(name))
+		o.onItemChanged(name)
+	})
+	itemSelector.Resize(itemSelector.MinSize().AddWidthHeight(20, 0))
+	if o.orderItem.Name != "" {
+		itemSelector.Selected = itemName(o.orderItem.Name)
+	}
+
+	o.renderer = &orderRenderer{
+		order:        o,
+		heading:      heading,
+		handle:       handle,
+		icon:         icon,
+		itemSelector: itemSelector,
+		amount:       amount,
+		increment:    increment,
+		decrement:    decrement,
+		menu:         menuButton,
+		remove:       remove,
+	}
+	return o.renderer
+}
+
+type orderRenderer struct {
+	order                              *Order
+	heading                            *widget.Label
+	handle                             *dragHandle
+	icon                               *widget.Icon
+	itemSelector                       *widget.Select
+	amount                             *shortcutEntry
+	increment, decrement, menu, remove *widget.Button
+}
+
+func (o *orderRenderer) Destroy() {
+}
+
+func (o *orderRenderer) headingHeight() float32 {
+	if o.heading.Hidden {
+		return 0
+	}
+	return o.heading.MinSize().Height
+}
+
+func (o *orderRenderer) Layout(size fyne.Size) {
+	padding := float32(4)
+	top := o.headingHeight()
+	o.heading.Move(fyne.NewPos(0, 0))
+	o.heading.Resize(fyne.NewSize(size.Width, top))
+
+	pos := fyne.NewPos(padding, top+padding)
+	rowMiddle := top + (size.Height-top)/2
+	buttonHeight := rowMiddle - (o.increment.Size().Height / 2)
+	o.handle.Move(fyne.NewPos(padding, rowMiddle-(o.handle.Size().Height/2)))
+	pos.X += o.handle.Size().Width + padding
+	o.icon.Move(fyne.NewPos(pos.X, rowMiddle-(o.icon.Size().Height/2)))
+	pos.X += o.icon.Size().Width + padding
+	o.itemSelector.Move(pos)
+	pos.X += o.itemSelector.Size().Width + (padding * 2)
+	pos.Y = buttonHeight
+	o.decrement.Move(pos)
+	pos.X += o.decrement.Size().Width + (padding * 2)
+	pos.Y = top + padding
+	o.amount.Move(pos)
+	pos.X += o.amount.Size().Width + (padding * 2)
+	pos.Y = buttonHeight
+	o.increment.Move(pos)
+	pos.X += o.increment.Size().Width + (padding * 2)
+	pos.X = size.Width - (padding + o.remove.MinSize().Width)
+	o.remove.Move(pos)
+	pos.X -= o.menu.Size().Width + (padding * 2)
+	o.menu.Move(pos)
+}
+
+func (o *orderRenderer) MinSize() fyne.Size {
+	width := o.handle.Size().Width + o.icon.Size().Width + o.itemSelector.MinSize().Width +
+		o.amount.Size().Width + 12
+	height := o.headingHeight() + o.itemSelector.MinSize().Height + 8
+	return fyne.NewSize(width, height)
+}
+
+func (o *orderRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{o.heading, o.handle, o.icon, o.itemSelector, o.amount,
+		o.increment, o.decrement, o.menu, o.remove}
+}
+
+func (o *orderRenderer) Refresh() {
+	o.heading.SetText(o.order.heading)
+	if o.order.heading == "" {
+		o.heading.Hide()
+	} else {
+		o.heading.Show()
+	}
+	o.itemSelector.Refresh()
+	o.amount.Refresh()
+}
+
+type dragHandle struct {
+	widget.Icon
+	onDragged func(*fyne.DragEvent)
+	onDragEnd func()
+}
+
+func newDragHandle(onDragged func(*fyne.DragEvent), onDragEnd func()) *dragHandle {
+	handle := &dragHandle{
+		onDragged: onDragged,
+		onDragEnd: onDragEnd,
+	}
+	handle.ExtendBaseWidget(handle)
+	handle.SetResource(theme.MoreVerticalIcon())
+	return handle
+}
+
+func (d *dragHandle) Cursor() desktop.Cursor {
+	return desktop.VResizeCursor
+}
+
+func (d *dragHandle) Dragged(event *fyne.DragEvent) {
+	d.onDragged(event)
+}
+
+func (d *dragHandle) DragEnd() {
+	d.onDragEnd()
+}
+
+type SummaryScreen struct {
+	widget.BaseWidget
+	ingredients []*ResultSummary
+	icons       *IconSet
+	renderer    *summaryScreenRenderer
+}
+
+func NewSummaryScreen(icons *IconSet) *SummaryScreen {
+	item := &SummaryScreen{
+		ingredients: make([]*ResultSummary, 0),
+		icons:       icons,
+	}
+	item.ExtendBaseWidget(item)
+	return item
+}
+
+func (s *SummaryScreen) Display(ingredients []ResultItem) {
+	s.ingredients = make([]*ResultSummary, 0)
+	for _, i := range ingredients {
+		s.ingredients = append(s.ingredients, NewResultSummary(i, s.icons))
+	}
+	if s.renderer != nil {
+		s.renderer.container = container.NewVBox()
+	}
+}
+
+func (s *SummaryScreen) CreateRenderer() fyne.WidgetRenderer {
+	item := &summaryScreenRenderer{
+		summaryScreen: s,
+		container:     container.NewVBox(),
+	}
+	s.renderer = item
+	return item
+}
+
+type summaryScreenRenderer struct {
+	summaryScreen *SummaryScreen
+	container     *fyne.Container
+}
+
+func (s *summaryScreenRenderer) Destroy() {
+}
+
+func (s *summaryScreenRenderer) Layout(size fyne.Size) {
+	s.container.Resize(size)
+	s.CheckChildren()
+	s.CheckLabels()
+}
+
+func (s *summaryScreenRenderer) CheckLabels() {
+	minWidth := float32(0)
+	for _, o := range s.container.Objects {
+		if item, ok := o.(*ResultSummary); ok {
+			width := item.renderer.itemLabel.MinSize().Width
+			if item.renderer.valueLabel.MinSize().Width > width {
+				width = item.renderer.valueLabel.MinSize().Width
+			}
+			if minWidth < width {
+				minWidth = width
+			}
+		}
+	}
+
+	for _, o := range s.container.Objects {
+		if item, ok := o.(*ResultSummary); ok {
+			item.setLabelWidth(minWidth)
+		}
+	}
+}
+
+func (s *summaryScreenRenderer) MinSize() fyne.Size {
+	return s.container.MinSize()
+}
+
+func (s *summaryScreenRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{s.container}
+}
+
+func (s *summaryScreenRenderer) CheckChildren() {
+	if len(s.container.Objects) == 0 &&
+		len(s.summaryScreen.ingredients) > 0 {
+		total := 0
+		group := ""
+		for index, ingredient := range s.summaryScreen.ingredients {
+			total += ingredient.result.Value
+			if index > 0 {
+				s.container.Add(NewSeparator())
+			}
+			if ingredient.result.Group != group {
+				group = ingredient.result.Group
+				heading := group
+				if heading == "" {
+					heading = tr("Ungrouped")
+				}
+				s.container.Add(widget.NewLabelWithStyle(heading, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+			}
+			s.container.Add(ingredient)
+		}
+		s.container.Add(NewSeparator())
+		s.container.Add(widget.NewLabel(
+			fmt.Sprintf(tr("Total: $%s"), formatNumber(total))))
+	}
+}
+
+func (s *summaryScreenRenderer) Refresh() {
+	s.CheckChildren()
+	s.CheckLabels()
+	s.container.Refresh()
+}
+
+type ResultSummary struct {
+	widget.BaseWidget
+	labelWidth float32
+	result     ResultItem
+	icons      *IconSet
+	renderer   *resultSummaryRenderer
+}
+
+func NewResultSummary(result ResultItem, icons *IconSet) *ResultSummary {
+	item := &ResultSummary{
+		result: result,
+		icons:  icons,
+	}
+	item.ExtendBaseWidget(item)
+	return item
+}
+
+func (r *ResultSummary) setLabelWidth(value float32) {
+	r.labelWidth = value
+}
+
+func (r *ResultSummary) CreateRenderer() fyne.WidgetRenderer {
+	nameLabel := widget.NewLabel(fmt.Sprintf("%s x %s",
+		formatNumber(r.result.Amount), itemName(r.result.Name)))
+	valueLabel := widget.NewLabel(formatMoney(r.result.Value))
+	valueLabel.SizeName = theme.SizeNameCaptionText
+
+	icon := widget.NewIcon(r.icons.Icon(r.result.Name))
+	icon.Resize(fyne.NewSquareSize(theme.IconInlineSize()))
+
+	subContainer := container.NewVBox()
+
+	renderer := &resultSummaryRenderer{
+		resultSummary: r,
+		badge:         newTypeBadge(),
+		icon:          icon,
+		itemLabel:     nameLabel,
+		valueLabel:    valueLabel,
+		subContainer:  subContainer,
+	}
+	renderer.refreshBadge()
+
+	r.renderer = renderer
+	return renderer
+}
+
+type resultSummaryRenderer struct {
+	resultSummary *ResultSummary
+	badge         *canvas.Rectangle
+	icon          *widget.Icon
+	itemLabel     *widget.Label
+	valueLabel    *widget.Label
+	subContainer  *fyne.Container
+}
+
+func (r *resultSummaryRenderer) Destroy() {
+}
+
+func (r *resultSummaryRenderer) Layout(fyne.Size) {
+	r.CheckChildren()
+	padding := float32(2)
+	pos := fyne.NewPos(padding, padding)
+
+	r.badge.Move(pos.AddXY(0, (r.itemLabel.MinSize().Height-r.icon.Size().Height)/2))
+	r.badge.Resize(fyne.NewSize(typeBadgeWidth, r.icon.Size().Height))
+	pos.X += typeBadgeWidth + padding
+	r.icon.Move(pos.AddXY(0, (r.itemLabel.MinSize().Height-r.icon.Size().Height)/2))
+	pos.X += r.icon.Size().Width
+	r.itemLabel.Move(pos)
+	r.itemLabel.Resize(fyne.NewSize(r.resultSummary.labelWidth, r.itemLabel.MinSize().Height))
+
+	widest := r.itemLabel.Size().Width
+	if r.valueLabel.Size().Width > widest {
+		widest = r.valueLabel.Size().Width
+	}
+	widest += typeBadgeWidth + padding + r.icon.Size().Width
+
+	pos.X = widest + (padding * 4)
+	r.subContainer.Resize(r.subContainer.MinSize())
+	r.subContainer.Move(pos)
+
+	pos.X = padding*2 + typeBadgeWidth + r.icon.Size().Width
+	pos.Y = r.itemLabel.Size().Height + padding
+	r.valueLabel.Resize(r.valueLabel.MinSize())
+	r.valueLabel.Move(pos)
+}
+
+func (r *resultSummaryRenderer) MinSize() fyne.Size {
+	size := r.itemLabel.MinSize()
+
+	if r.valueLabel.MinSize().Width > size.Width {
+		size.Width = r.valueLabel.MinSize().Width
+	}
+
+	if r.resultSummary.labelWidth > size.Width {
+		size.Width = float32(r.resultSummary.labelWidth)
+	}
+
+	size.Width += typeBadgeWidth + 2 + r.icon.Size().Width + r.subContainer.MinSize().Width
+	size.Height += r.valueLabel.MinSize().Height
+
+	if r.subContainer.MinSize().Height > size.Height {
+		size.Height = r.subContainer.MinSize().Height
+	}
+
+	return size
+}
+
+func (r *resultSummaryRenderer) refreshBadge() {
+	if colorName := r.resultSummary.icons.ColorName(r.resultSummary.result.Name); colorName != "" {
+		r.badge.FillColor = theme.Color(colorName)
+		r.badge.Refresh()
+	}
+}
+
+func (r *resultSummaryRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.badge, r.icon, r.itemLabel, r.valueLabel, r.subContainer}
+}
+
+func (r *resultSummaryRenderer) CheckChildren() {
+	if len(r.subContainer.Objects) == 0 &&
+		len(r.resultSummary.result.Ingredients) > 0 {
+		for index, ingredient := range r.resultSummary.result.Ingredients {
+			if index > 0 {
+				r.subContainer.Add(NewSeparator())
+			}
+			label := NewIconLabel(fmt.Sprintf("%s x %s", formatNumber(ingredient.Amount), itemName(ingredient.Name)),
+				r.resultSummary.icons.Icon(ingredient.Name))
+			label.SetColorName(r.resultSummary.icons.ColorName(ingredient.Name))
+			r.subContainer.Add(label)
+		}
+	}
+}
+
+func (r *resultSummaryRenderer) Refresh() {
+	r.refreshBadge()
+	r.CheckChildren()
+	r.subContainer.Refresh()
+}
+
+var simChartColours = []fyne.ThemeColorName{
+	theme.ColorNamePrimary,
+	theme.ColorNameSuccess,
+	theme.ColorNameWarning,
+}
+
+// SimChart draws the cash over time of one or more simulation runs
+type SimChart struct {
+	widget.BaseWidget
+	results []SimResult
+}
+
+func NewSimChart() *SimChart {
+	item := &SimChart{
+		results: make([]SimResult, 0),
+	}
+	item.ExtendBaseWidget(item)
+	return item
+}
+
+func (c *SimChart) Display(results []SimResult) {
+	c.results = results
+	c.Refresh()
+}
+
+func (c *SimChart) CreateRenderer() fyne.WidgetRenderer {
+	return &simChartRenderer{
+		chart:     c,
+		axis:      canvas.NewRectangle(theme.Color(theme.ColorNameForeground)),
+		maxLabel:  canvas.NewText("", theme.Color(theme.ColorNameForeground)),
+		timeLabel: canvas.NewText("", theme.Color(theme.ColorNameForeground)),
+		legend:    container.NewHBox(),
+	}
+}
+
+type simChartRenderer struct {
+	chart     *SimChart
+	axis      *canvas.Rectangle
+	maxLabel  *canvas.Text
+	timeLabel *canvas.Text
+	legend    *fyne.Container
+	lines     []fyne.CanvasObject
+}
+
+func (r *simChartRenderer) Destroy() {
+}
+
+func (r *simChartRenderer) Layout(size fyne.Size) {
+	legendHeight := r.legend.MinSize().Height
+	r.legend.Resize(fyne.NewSize(size.Width, legendHeight))
+	r.legend.Move(fyne.NewPos(0, 0))
+	r.maxLabel.Move(fyne.NewPos(4, legendHeight))
+	r.timeLabel.Move(fyne.NewPos(size.Width-r.timeLabel.MinSize().Width, size.Height-r.timeLabel.MinSize().Height))
+
+	top := legendHeight + r.maxLabel.MinSize().Height
+	bottom := size.Height - r.timeLabel.MinSize().Height
+	r.axis.Move(fyne.NewPos(0, bottom))
+	r.axis.Resize(fyne.NewSize(size.Width, 1))
+
+	maxCash, maxTime := r.getExtent()
+	r.lines = r.lines[:0]
+	for index, result := range r.chart.results {
+		colour := theme.Color(simChartColours[index%len(simChartColours)])
+		for sample := 1; sample < len(result.Samples); sample++ {
+			from, to := result.Samples[sample-1], result.Samples[sample]
+			line := canvas.NewLine(colour)
+			line.StrokeWidth = 2
+			line.Position1 = fyne.NewPos(
+				size.Width*float32(from.Time)/float32(maxTime),
+				bottom-(bottom-top)*float32(from.Cash)/float32(maxCash))
+			line.Position2 = fyne.NewPos(
+				size.Width*float32(to.Time)/float32(maxTime),
+				bottom-(bottom-top)*float32(to.Cash)/float32(maxCash))
+			r.lines = append(r.lines, line)
+		}
+	}
+}
+
+func (r *simChartRenderer) getExtent() (maxCash int, maxTime time.Duration) {
+	maxCash, maxTime = 1, time.Second
+	for _, result := range r.chart.results {
+		for _, sample := range result.Samples {
+			maxCash = max(maxCash, sample.Cash)
+			maxTime = max(maxTime, sample.Time)
+		}
+	}
+	return
+}
+
+func (r *simChartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(300, 200)
+}
+
+func (r *simChartRenderer) Objects() []fyne.CanvasObject {
+	return append([]fyne.CanvasObject{r.legend, r.axis, r.maxLabel, r.timeLabel}, r.lines...)
+}
+
+func (r *simChartRenderer) Refresh() {
+	maxCash, maxTime := r.getExtent()
+	foreground := theme.Color(theme.ColorNameForeground)
+	r.axis.FillColor, r.maxLabel.Color, r.timeLabel.Color = foreground, foreground, foreground
+	r.maxLabel.Text = formatMoney(maxCash)
+	r.timeLabel.Text = maxTime.String()
+	r.legend.RemoveAll()
+	for index, result := range r.chart.results {
+		swatch := canvas.NewRectangle(theme.Color(simChartColours[index%len(simChartColours)]))
+		swatch.SetMinSize(fyne.NewSquareSize(12))
+		r.legend.Add(container.NewCenter(swatch))
+		r.legend.Add(widget.NewLabel(tr(result.Strategy.String())))
+	}
+	r.Layout(r.chart.Size())
+	canvas.Refresh(r.chart)
+}
+
+const typeBadgeWidth = 3
+
+// newTypeBadge is a strip in an item type's colour, so the type still shows
+// next to icons dropped in by the user.
+func newTypeBadge() *canvas.Rectangle {
+	badge := canvas.NewRectangle(color.Transparent)
+	badge.CornerRadius = typeBadgeWidth / 2
+	badge.SetMinSize(fyne.NewSize(typeBadgeWidth, 0))
+	return badge
+}
+
+// IconLabel is a caption sized label with an optional icon and item type
+// badge before it
+type IconLabel struct {
+	widget.BaseWidget
+	badge     *canvas.Rectangle
+	colorName fyne.ThemeColorName
+	icon      *widget.Icon
+	label     *widget.Label
+}
+
+func NewIconLabel(text string, icon fyne.Resource) *IconLabel {
+	item := &IconLabel{
+		badge: newTypeBadge(),
+		icon:  widget.NewIcon(icon),
+		label: widget.NewLabel(text),
+	}
+	item.label.SizeName = theme.SizeNameCaptionText
+	item.ExtendBaseWidget(item)
+	item.SetIcon(icon)
+	return item
+}
+
+func (l *IconLabel) SetText(text string) {
+	l.label.SetText(text)
+}
+
+func (l *IconLabel) SetIcon(icon fyne.Resource) {
+	l.icon.SetResource(icon)
+	if icon == nil {
+		l.icon.Hide()
+	} else {
+		l.icon.Show()
+	}
+	l.Refresh()
+}
+
+// SetColorName shows a badge in the colour, an empty name hides it
+func (l *IconLabel) SetColorName(name fyne.ThemeColorName) {
+	l.colorName = name
+	l.Refresh()
+}
+
+func (l *IconLabel) Refresh() {
+	if l.colorName == "" {
+		l.badge.Hide()
+	} else {
+		l.badge.FillColor = theme.Color(l.colorName)
+		l.badge.Show()
+	}
+	l.BaseWidget.Refresh()
+}
+
+func (l *IconLabel) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewBorder(nil, nil, container.NewHBox(l.badge, l.icon), nil, l.label))
+}
+
+// AdaptiveContainer asks getContent for a wide or narrow layout depending on
+// whether it's at least threshold wide, swapping when the width crosses it.
+type AdaptiveContainer struct {
+	widget.BaseWidget
+	threshold  float32
+	wide       bool
+	getContent func(wide bool) fyne.CanvasObject
+	stack      *fyne.Container
+}
+
+func NewAdaptiveContainer(threshold float32, getContent func(wide bool) fyne.CanvasObject) *AdaptiveContainer {
+	item := &AdaptiveContainer{
+		threshold:  threshold,
+		getContent: getContent,
+		stack:      container.NewStack(getContent(false)),
+	}
+	item.ExtendBaseWidget(item)
+	return item
+}
+
+func (c *AdaptiveContainer) Resize(size fyne.Size) {
+	if wide := size.Width >= c.threshold; wide != c.wide {
+		c.wide = wide
+		c.stack.Objects = []fyne.CanvasObject{c.getContent(wide)}
+		c.stack.Refresh()
+	}
+	c.BaseWidget.Resize(size)
+}
+
+func (c *AdaptiveContainer) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(c.stack)
+}
+
+// Separator is a line fading out to the right, coloured by the theme
+type Separator struct {
+	widget.BaseWidget
+}
+
+func NewSeparator() *Separator {
+	item := &Separator{}
+	item.ExtendBaseWidget(item)
+	return item
+}
+
+func (s *Separator) CreateRenderer() fyne.WidgetRenderer {
+	renderer := &separatorRenderer{gradient: canvas.NewHorizontalGradient(color.Transparent, color.Transparent)}
+	renderer.Refresh()
+	return renderer
+}
+
+type separatorRenderer struct {
+	gradient *canvas.LinearGradient
+}
+
+func (r *separatorRenderer) Destroy() {
+}
+
+func (r *separatorRenderer) Layout(size fyne.Size) {
+	r.gradient.Resize(size)
+}
+
+func (r *separatorRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(theme.SeparatorThicknessSize(), theme.SeparatorThicknessSize())
+}
+
+func (r *separatorRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.gradient}
+}
+
+func (r *separatorRenderer) Refresh() {
+	r.gradient.StartColor = withAlpha(theme.Color(theme.ColorNameForeground), 0x50)
+	r.gradient.EndColor = color.Transparent
+	r.gradient.Refresh()
+}
+
+// shortcutEntry is an entry that also passes custom shortcuts on to the
+// window, so the app's shortcuts still work while typing in it.
+type shortcutEntry struct {
+	widget.Entry
+}
+
+func newShortcutEntry() *shortcutEntry {
+	entry := &shortcutEntry{}
+	entry.ExtendBaseWidget(entry)
+	return entry
+}
+
+func (e *shortcutEntry) TypedShortcut(shortcut fyne.Shortcut) {
+	e.Entry.TypedShortcut(shortcut)
+	if _, ok := shortcut.(*desktop.CustomShortcut); !ok {
+		return
+	}
+	if c, ok := fyne.CurrentApp().Driver().CanvasForObject(e).(fyne.Shortcutable); ok {
+		c.TypedShortcut(shortcut)
+	}
+}